@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 )
@@ -10,7 +11,6 @@ import (
 const ACTION_PATH = "auth/actions"
 
 func (c *GFWClient) GetActions() (*[]Action, error) {
-	fmt.Println("url", fmt.Sprintf("%s/%s", c.HostURL, ACTION_PATH))
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", c.HostURL, ACTION_PATH), nil)
 	if err != nil {
 		return nil, err
@@ -21,7 +21,7 @@ func (c *GFWClient) GetActions() (*[]Action, error) {
 	}
 
 	actions := []Action{}
-	fmt.Println(string(body))
+	log.Printf("[DEBUG] actions: %s", body)
 	err = json.Unmarshal(body, &actions)
 	if err != nil {
 		return nil, err
@@ -1,11 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,6 +17,13 @@ type GFWClient struct {
 	HostURL    string
 	HTTPClient *http.Client
 	Token      string
+	// DisallowUnknownFields makes decoding datasets, dataviews and workspaces
+	// fail on fields missing from their types, tests set it to notice changes
+	// of the API.
+	DisallowUnknownFields bool
+	// ReportUnknownFields sets the UnknownFields of the datasets, dataviews
+	// and workspaces decoded.
+	ReportUnknownFields bool
 }
 
 // NewClient -
@@ -42,6 +52,68 @@ func valuesToRaw(query url.Values) string {
 	return rawQuery
 }
 
+// unmarshal decodes a response body, honouring DisallowUnknownFields and
+// ReportUnknownFields.
+func (c *GFWClient) unmarshal(body []byte, v interface{}) error {
+	if c.DisallowUnknownFields {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(v); err != nil {
+			return err
+		}
+	} else if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	if !c.ReportUnknownFields {
+		return nil
+	}
+	paths, err := UnknownFields(body, v)
+	if err != nil {
+		return err
+	}
+	switch value := v.(type) {
+	case *Dataset:
+		value.UnknownFields = paths
+	case *Dataview:
+		value.UnknownFields = paths
+	case *Workspace:
+		value.UnknownFields = paths
+	}
+	return nil
+}
+
+// getAllPages reads every page of a paginated list, following nextOffset.
+// query holds the parameters of the list, without the offset.
+func getAllPages[T any](c *GFWClient, path string, query url.Values) ([]T, error) {
+	entries := []T{}
+	offset := 0
+	for {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", c.HostURL, path), nil)
+		if err != nil {
+			return nil, err
+		}
+		pageQuery := url.Values{}
+		for k, v := range query {
+			pageQuery[k] = v
+		}
+		pageQuery.Set("offset", strconv.Itoa(offset))
+		req.URL.RawQuery = pageQuery.Encode()
+		body, err := c.doRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		page := Pagination[T]{}
+		if err := c.unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+		entries = append(entries, page.Entries...)
+		if page.NextOffset == nil || *page.NextOffset <= offset {
+			return entries, nil
+		}
+		offset = *page.NextOffset
+	}
+}
+
 func (c *GFWClient) doRequest(req *http.Request) ([]byte, error) {
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.Token))
 	query := req.URL.Query()
@@ -56,7 +128,7 @@ func (c *GFWClient) doRequest(req *http.Request) ([]byte, error) {
 
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		fmt.Println(string(body))
+		log.Printf("[DEBUG] partial response body: %s", body)
 		return nil, err
 	}
 	if res.StatusCode >= 500 {
@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -20,7 +22,7 @@ func (c *GFWClient) GetDatasets() (*[]Dataset, error) {
 	}
 
 	datasets := Pagination[Dataset]{}
-	err = json.Unmarshal(body, &datasets)
+	err = c.unmarshal(body, &datasets)
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +30,12 @@ func (c *GFWClient) GetDatasets() (*[]Dataset, error) {
 	return &datasets.Entries, nil
 }
 
+// GetAllDatasets returns every dataset, GetDatasets only reads the first
+// page.
+func (c *GFWClient) GetAllDatasets() ([]Dataset, error) {
+	return getAllPages[Dataset](c, DATASET_PATH, url.Values{"includes[0]": {"BACKEND_CONFIGURATION"}})
+}
+
 func (c *GFWClient) GetDataset(id string) (*Dataset, error) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s?includes[0]=BACKEND_CONFIGURATION&cache=false", c.HostURL, DATASET_PATH, id), nil)
 	if err != nil {
@@ -38,7 +46,7 @@ func (c *GFWClient) GetDataset(id string) (*Dataset, error) {
 		return nil, err
 	}
 	dataset := Dataset{}
-	err = json.Unmarshal(body, &dataset)
+	err = c.unmarshal(body, &dataset)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +67,7 @@ func (c *GFWClient) DeleteDataset(id string) (*Dataset, error) {
 		return nil, nil
 	}
 	dataset := Dataset{}
-	err = json.Unmarshal(body, &dataset)
+	err = c.unmarshal(body, &dataset)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +81,7 @@ func (c *GFWClient) UpdateDataset(id string, dataset CreateDataset) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(bodyReq))
+	log.Printf("[DEBUG] dataset %s update: %s", id, bodyReq)
 	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/%s/%s?includes[0]=BACKEND_CONFIGURATION", c.HostURL, DATASET_PATH, id), strings.NewReader(string(bodyReq)))
 	req.Header.Add("content-type", "application/json")
 	if err != nil {
@@ -108,7 +116,7 @@ func (c *GFWClient) CreateDataset(dataset CreateDataset) (*Dataset, error) {
 	}
 
 	newDataset := Dataset{}
-	err = json.Unmarshal(body, &newDataset)
+	err = c.unmarshal(body, &newDataset)
 	if err != nil {
 		return nil, err
 	}
@@ -129,3 +137,161 @@ func (c *GFWClient) checkExistDataset(id string) (*Dataset, error) {
 
 	return dataset, nil
 }
+
+type datasetAlias struct {
+	Alias []string `json:"alias"`
+}
+
+// GetDatasetsByAlias returns every dataset currently holding the alias.
+func (c *GFWClient) GetDatasetsByAlias(alias string) ([]Dataset, error) {
+	datasets, err := c.GetAllDatasets()
+	if err != nil {
+		return nil, err
+	}
+	holders := []Dataset{}
+	for _, d := range datasets {
+		for _, a := range d.Alias {
+			if a == alias {
+				holders = append(holders, d)
+				break
+			}
+		}
+	}
+	return holders, nil
+}
+
+// SetDatasetAlias replaces the aliases of a dataset. Unlike UpdateDataset an
+// empty list is sent as is, so the last alias of a dataset can be removed.
+func (c *GFWClient) SetDatasetAlias(id string, alias []string) error {
+	if alias == nil {
+		alias = []string{}
+	}
+	bodyReq, err := json.Marshal(datasetAlias{Alias: alias})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/%s/%s", c.HostURL, DATASET_PATH, id), strings.NewReader(string(bodyReq)))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("content-type", "application/json")
+	_, err = c.doRequest(req)
+	return err
+}
+
+// MoveDatasetAlias points alias at the target dataset. The alias is first
+// removed from every other dataset holding it and then added to the target,
+// so there is never more than one holder. If any step fails the removed
+// aliases are given back to their previous datasets.
+func (c *GFWClient) MoveDatasetAlias(alias, target string) error {
+	holders, err := c.GetDatasetsByAlias(alias)
+	if err != nil {
+		return err
+	}
+
+	removed := []Dataset{}
+	rollback := func(cause error) error {
+		for _, d := range removed {
+			if err := c.SetDatasetAlias(d.ID, d.Alias); err != nil {
+				return fmt.Errorf("%v (rollback of alias %q on dataset %q failed: %v)", cause, alias, d.ID, err)
+			}
+		}
+		return cause
+	}
+
+	for _, d := range holders {
+		if d.ID == target {
+			continue
+		}
+		if err := c.SetDatasetAlias(d.ID, removeString(d.Alias, alias)); err != nil {
+			return rollback(err)
+		}
+		removed = append(removed, d)
+	}
+
+	dataset, err := c.GetDataset(target)
+	if err != nil {
+		return rollback(err)
+	}
+	for _, a := range dataset.Alias {
+		if a == alias {
+			return nil
+		}
+	}
+	if err := c.SetDatasetAlias(target, append(dataset.Alias, alias)); err != nil {
+		return rollback(err)
+	}
+	return nil
+}
+
+// datasetStatus is the body sent to change the status of a dataset, and to
+// clear its aliases when the list is set.
+type datasetStatus struct {
+	Status string    `json:"status"`
+	Alias  *[]string `json:"alias,omitempty"`
+}
+
+// DeprecateDataset marks the dataset as deprecated so it stays readable, and
+// optionally frees its aliases for the datasets replacing it.
+func (c *GFWClient) DeprecateDataset(id string, stripAliases bool) error {
+	body := datasetStatus{Status: "deprecated"}
+	if stripAliases {
+		body.Alias = &[]string{}
+	}
+	bodyReq, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/%s/%s", c.HostURL, DATASET_PATH, id), strings.NewReader(string(bodyReq)))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("content-type", "application/json")
+	_, err = c.doRequest(req)
+	return err
+}
+
+// RemoveDatasetAlias removes alias from the dataset if it holds it.
+func (c *GFWClient) RemoveDatasetAlias(id, alias string) error {
+	dataset, err := c.GetDataset(id)
+	if err != nil {
+		return err
+	}
+	aliases := removeString(dataset.Alias, alias)
+	if len(aliases) == len(dataset.Alias) {
+		return nil
+	}
+	return c.SetDatasetAlias(id, aliases)
+}
+
+func removeString(array []string, s string) []string {
+	list := []string{}
+	for _, v := range array {
+		if v != s {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// NewCreateDataset builds the payload that recreates an existing dataset.
+func NewCreateDataset(dataset Dataset) CreateDataset {
+	return CreateDataset{
+		ID:              dataset.ID,
+		Name:            dataset.Name,
+		Description:     OptionalString(dataset.Description),
+		Type:            dataset.Type,
+		Alias:           dataset.Alias,
+		StartDate:       OptionalString(dataset.StartDate),
+		EndDate:         OptionalString(dataset.EndDate),
+		Unit:            OptionalString(dataset.Unit),
+		Status:          dataset.Status,
+		Category:        dataset.Category,
+		Subcategory:     OptionalString(dataset.Subcategory),
+		Source:          OptionalString(dataset.Source),
+		Configuration:   dataset.Configuration,
+		RelatedDatasets: dataset.RelatedDatasets,
+		Filters:         dataset.Filters,
+		Documentation:   dataset.Documentation,
+	}
+}
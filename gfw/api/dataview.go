@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -20,7 +21,7 @@ func (c *GFWClient) GetDataviews() (*[]Dataview, error) {
 	}
 
 	dataviews := Pagination[Dataview]{}
-	err = json.Unmarshal(body, &dataviews)
+	err = c.unmarshal(body, &dataviews)
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +29,12 @@ func (c *GFWClient) GetDataviews() (*[]Dataview, error) {
 	return &dataviews.Entries, nil
 }
 
+// GetAllDataviews returns every dataview, GetDataviews only reads the first
+// page.
+func (c *GFWClient) GetAllDataviews() ([]Dataview, error) {
+	return getAllPages[Dataview](c, DATAVIEW_PATH, url.Values{})
+}
+
 func (c *GFWClient) GetDataview(id string) (*Dataview, error) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", c.HostURL, DATAVIEW_PATH, id), nil)
 	if err != nil {
@@ -39,7 +46,7 @@ func (c *GFWClient) GetDataview(id string) (*Dataview, error) {
 	}
 
 	dataview := Dataview{}
-	err = json.Unmarshal(body, &dataview)
+	err = c.unmarshal(body, &dataview)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +67,7 @@ func (c *GFWClient) DeleteDataview(id string) (*Dataview, error) {
 		return nil, nil
 	}
 	dataview := Dataview{}
-	err = json.Unmarshal(body, &dataview)
+	err = c.unmarshal(body, &dataview)
 	if err != nil {
 		return nil, err
 	}
@@ -68,9 +75,11 @@ func (c *GFWClient) DeleteDataview(id string) (*Dataview, error) {
 	return &dataview, nil
 }
 
-func (c *GFWClient) UpdateDataview(id string, dataview CreateDataview) error {
+// UpdateDataview sends a partial update, attributes set to nil in the patch
+// are cleared on the server.
+func (c *GFWClient) UpdateDataview(id string, patch Patch) error {
 
-	bodyReq, err := json.Marshal(dataview)
+	bodyReq, err := json.Marshal(patch)
 	if err != nil {
 		return err
 	}
@@ -109,7 +118,7 @@ func (c *GFWClient) CreateDataview(dataview CreateDataview) (*Dataview, error) {
 	}
 
 	newDataview := Dataview{}
-	err = json.Unmarshal(body, &newDataview)
+	err = c.unmarshal(body, &newDataview)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,501 @@
+// Package fake implements an in-memory GFW API served with httptest, covering
+// the datasets, dataviews, workspaces and auth endpoints used by
+// api.GFWClient. It lets the provider be tested without a live API.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+)
+
+// Token is the bearer token accepted by the server.
+const Token = "fake-token"
+
+const (
+	Datasets    = "datasets"
+	Dataviews   = "dataviews"
+	Workspaces  = "workspaces"
+	Actions     = "auth/actions"
+	Resources   = "auth/resources"
+	Permissions = "auth/permissions"
+	Roles       = "auth/roles"
+	UserGroups  = "auth/user-groups"
+)
+
+var (
+	rolePermissionPath = regexp.MustCompile(`^auth/roles/(\d+)/permission/(\d+)$`)
+	userGroupRolePath  = regexp.MustCompile(`^auth/user-groups/(\d+)/role/(\d+)$`)
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// Fault makes the server fail or slow down the matching requests.
+type Fault struct {
+	// Method matches every method when empty.
+	Method string
+	// Path is a prefix of the request path without the leading slash,
+	// empty matches every path.
+	Path string
+	// Status is the status code returned, 0 only adds the latency.
+	Status int
+	// Latency is waited before answering.
+	Latency time.Duration
+	// Times is the number of requests affected, 0 for every request.
+	Times int
+}
+
+type collection struct {
+	ids       []string
+	items     map[string]map[string]interface{}
+	paginated bool
+	autoID    bool
+}
+
+// Server is an in-memory GFW API. Objects are kept as decoded JSON so fields
+// unknown to the client are stored and returned as sent.
+type Server struct {
+	*httptest.Server
+
+	// PageSize is the limit of the paginated lists requested without one,
+	// as the API does. Zero returns every entry.
+	PageSize int
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	faults      []*Fault
+	requests    []Request
+	nextID      int
+}
+
+// NewServer starts a server with an empty store. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		collections: map[string]*collection{
+			Datasets:    {paginated: true},
+			Dataviews:   {paginated: true, autoID: true},
+			Workspaces:  {paginated: true},
+			Actions:     {autoID: true},
+			Resources:   {autoID: true},
+			Permissions: {autoID: true},
+			Roles:       {autoID: true},
+			UserGroups:  {autoID: true},
+		},
+	}
+	for _, c := range s.collections {
+		c.items = map[string]map[string]interface{}{}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// APIClient returns a client authenticated against the server.
+func (s *Server) APIClient() *api.GFWClient {
+	c, _ := api.NewClient(s.URL, Token)
+	return c
+}
+
+// InjectFault adds a fault, the first matching fault is applied.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes every fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// ResetRequests forgets the requests received so far.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// Get returns a stored object, bypassing authentication and faults.
+func (s *Server) Get(collection, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.collections[collection].items[id]
+	return obj, ok
+}
+
+// Put stores an object as if it was changed outside of Terraform. Objects of
+// collections with generated IDs get one when they have none.
+func (s *Server) Put(collection string, v interface{}) (string, error) {
+	obj, err := toObject(v)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.collections[collection]
+	if _, ok := obj["id"]; !ok && c.autoID {
+		s.nextID++
+		obj["id"] = s.nextID
+	}
+	id := fmt.Sprint(obj["id"])
+	c.put(id, obj)
+	return id, nil
+}
+
+// Remove deletes an object as if it was deleted outside of Terraform.
+func (s *Server) Remove(collection, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections[collection].remove(id)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	path := strings.Trim(r.URL.Path, "/")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: path, Body: string(body)})
+	fault := s.matchFault(r.Method, path)
+	s.mu.Unlock()
+	if fault != nil {
+		time.Sleep(fault.Latency)
+		if fault.Status != 0 {
+			writeError(w, fault.Status, fmt.Sprintf("fault injected on %s %s", r.Method, path))
+			return
+		}
+	}
+
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := rolePermissionPath.FindStringSubmatch(path); m != nil {
+		s.handleMembership(w, r.Method, Roles, m[1], "permissions", Permissions, m[2])
+		return
+	}
+	if m := userGroupRolePath.FindStringSubmatch(path); m != nil {
+		s.handleMembership(w, r.Method, UserGroups, m[1], "roles", Roles, m[2])
+		return
+	}
+
+	name, id := s.route(path)
+	if name == "" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown path %s", path))
+		return
+	}
+	c := s.collections[name]
+
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		s.list(w, r, c)
+	case id == "" && r.Method == http.MethodPost:
+		s.create(w, name, c, body)
+	case id != "" && r.Method == http.MethodGet:
+		obj, ok := c.items[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", name, id))
+			return
+		}
+		writeJSON(w, http.StatusOK, obj)
+	case id != "" && (r.Method == http.MethodPut || r.Method == http.MethodPatch):
+		s.update(w, name, c, id, body)
+	case id != "" && r.Method == http.MethodDelete:
+		obj, ok := c.items[id]
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", name, id))
+			return
+		}
+		c.remove(id)
+		writeJSON(w, http.StatusOK, obj)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed on %s", r.Method, path))
+	}
+}
+
+// route splits a path into its collection and object ID.
+func (s *Server) route(path string) (string, string) {
+	for name := range s.collections {
+		if path == name {
+			return name, ""
+		}
+		if strings.HasPrefix(path, name+"/") {
+			return name, strings.TrimPrefix(path, name+"/")
+		}
+	}
+	return "", ""
+}
+
+func (s *Server) matchFault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != method) || !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request, c *collection) {
+	entries := make([]map[string]interface{}, 0, len(c.ids))
+	for _, id := range c.ids {
+		entries = append(entries, c.items[id])
+	}
+	if !c.paginated {
+		writeJSON(w, http.StatusOK, entries)
+		return
+	}
+
+	total := len(entries)
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if offset > total {
+		offset = total
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = s.PageSize
+	}
+	if limit <= 0 || offset+limit > total {
+		limit = total - offset
+	}
+	page := api.Pagination[map[string]interface{}]{
+		Total:    total,
+		Limit:    &limit,
+		Offset:   &offset,
+		Metadata: map[string]interface{}{},
+		Entries:  entries[offset : offset+limit],
+	}
+	if next := offset + limit; next < total {
+		page.NextOffset = &next
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) create(w http.ResponseWriter, name string, c *collection, body []byte) {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+
+	if c.autoID {
+		s.nextID++
+		obj["id"] = s.nextID
+	} else if id, _ := obj["id"].(string); id == "" {
+		if name != Workspaces {
+			writeError(w, http.StatusUnprocessableEntity, "id is required")
+			return
+		}
+		// Like the API, workspaces created without an ID get one from
+		// their name.
+		workspaceName, _ := obj["name"].(string)
+		public, _ := obj["public"].(bool)
+		obj["id"] = api.WorkspaceID(workspaceName, public)
+	}
+	id := fmt.Sprint(obj["id"])
+	if _, ok := c.items[id]; ok {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s %s already exists", name, id))
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	obj["createdAt"] = now
+	switch name {
+	case Dataviews:
+		obj["updatedAt"] = now
+	case Permissions:
+		if err := s.resolvePermission(obj); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+	case Roles:
+		obj["permissions"] = []interface{}{}
+	case UserGroups:
+		obj["roles"] = []interface{}{}
+	}
+	removeNulls(obj)
+
+	c.put(id, obj)
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+// update merges the body into the object, null values remove the attribute.
+func (s *Server) update(w http.ResponseWriter, name string, c *collection, id string, body []byte) {
+	obj, ok := c.items[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", name, id))
+		return
+	}
+	changes := map[string]interface{}{}
+	if err := json.Unmarshal(body, &changes); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid body: %v", err))
+		return
+	}
+	delete(changes, "id")
+	for k, v := range changes {
+		if v == nil {
+			delete(obj, k)
+		} else {
+			obj[k] = v
+		}
+	}
+	if name == Dataviews {
+		obj["updatedAt"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// resolvePermission replaces the action and resource IDs of a new permission
+// with the objects they reference.
+func (s *Server) resolvePermission(obj map[string]interface{}) error {
+	for key, ref := range map[string]string{"actionId": Actions, "resourceId": Resources} {
+		referenced, ok := s.collections[ref].items[fmt.Sprint(obj[key])]
+		if !ok {
+			return fmt.Errorf("%s %v not found", key, obj[key])
+		}
+		delete(obj, key)
+		obj[strings.TrimSuffix(key, "Id")] = referenced
+	}
+	return nil
+}
+
+// handleMembership adds or removes a member, such as a permission of a role,
+// and answers with the updated parent.
+func (s *Server) handleMembership(w http.ResponseWriter, method, parentName, parentId, key, memberName, memberId string) {
+	parent, ok := s.collections[parentName].items[parentId]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", parentName, parentId))
+		return
+	}
+	member, ok := s.collections[memberName].items[memberId]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", memberName, memberId))
+		return
+	}
+
+	members, _ := parent[key].([]interface{})
+	kept := []interface{}{}
+	for _, m := range members {
+		if fmt.Sprint(m.(map[string]interface{})["id"]) != memberId {
+			kept = append(kept, m)
+		}
+	}
+	switch method {
+	case http.MethodPost:
+		kept = append(kept, member)
+	case http.MethodDelete:
+		if len(kept) == len(members) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not in %s %s", memberName, memberId, parentName, parentId))
+			return
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("%s not allowed", method))
+		return
+	}
+	parent[key] = kept
+	writeJSON(w, http.StatusOK, parent)
+}
+
+func (c *collection) put(id string, obj map[string]interface{}) {
+	if _, ok := c.items[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = obj
+}
+
+func (c *collection) remove(id string) {
+	if _, ok := c.items[id]; !ok {
+		return
+	}
+	delete(c.items, id)
+	for i, v := range c.ids {
+		if v == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+func toObject(v interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	return obj, json.Unmarshal(body, &obj)
+}
+
+func removeNulls(obj map[string]interface{}) {
+	keys := []string{}
+	for k, v := range obj {
+		if v == nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		delete(obj, k)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with the AppError body returned by the GFW API.
+func writeError(w http.ResponseWriter, status int, detail string) {
+	var appError *api.AppError
+	switch status {
+	case api.UnauthorizedCode:
+		appError = api.NewUnauthorizedStandard(detail)
+	case api.ForbiddenCode:
+		appError = api.NewForbiddenStandard(detail)
+	case api.NotFoundCode:
+		appError = api.NewNotFoundStandard(detail)
+	case api.UnprocessableEntityCode:
+		appError = api.NewUnprocessableEntityStandard([]api.MessageError{{Title: api.UnprocessableEntityMessage, Detail: detail}})
+	case api.ServiceUnavailableCode:
+		appError = api.NewServerUnavailableStandard(detail)
+	default:
+		appError = api.NewAppError(status, http.StatusText(status), []api.MessageError{{Title: http.StatusText(status), Detail: detail}})
+	}
+	writeJSON(w, status, appError)
+}
@@ -0,0 +1,246 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+)
+
+func TestDatasetLifecycle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.APIClient()
+
+	_, err := c.CreateDataset(api.CreateDataset{
+		ID:          "test-dataset:v1",
+		Name:        "Test",
+		Description: api.NewNullable("description"),
+		Alias:       []string{"test-dataset"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.UpdateDataset("test-dataset:v1", api.CreateDataset{Name: "Renamed", Description: api.NewNull[string]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataset, err := c.GetDataset("test-dataset:v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataset.Name != "Renamed" || dataset.Description != "" || len(dataset.Alias) != 1 {
+		t.Errorf("unexpected dataset after update: %+v", dataset)
+	}
+
+	if _, err := c.DeleteDataset("test-dataset:v1"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetDataset("test-dataset:v1")
+	if appError, ok := err.(api.AppError); !ok || appError.Code != api.NotFoundCode {
+		t.Errorf("expected a not found AppError, got %v", err)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	for i := 0; i < 5; i++ {
+		if _, err := s.Put(Datasets, map[string]interface{}{"id": fmt.Sprintf("dataset-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		query      string
+		entries    int
+		nextOffset string
+	}{
+		{"", 5, ""},
+		{"limit=2", 2, "2"},
+		{"limit=2&offset=4", 1, ""},
+	} {
+		page := api.Pagination[api.Dataset]{}
+		if err := getJSON(s.URL+"/datasets?"+tc.query, &page); err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 5 || len(page.Entries) != tc.entries {
+			t.Errorf("%q: got %d of %d entries, expected %d of 5", tc.query, len(page.Entries), page.Total, tc.entries)
+		}
+		next := ""
+		if page.NextOffset != nil {
+			next = strconv.Itoa(*page.NextOffset)
+		}
+		if next != tc.nextOffset {
+			t.Errorf("%q: got next offset %q, expected %q", tc.query, next, tc.nextOffset)
+		}
+	}
+}
+
+// Lists requested without a limit get PageSize entries, the client follows
+// nextOffset to read them all.
+func TestGetAllPages(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.PageSize = 2
+	for i := 0; i < 5; i++ {
+		dataset := map[string]interface{}{"id": fmt.Sprintf("dataset-%d", i)}
+		if i == 4 {
+			dataset["alias"] = []string{"latest"}
+		}
+		if _, err := s.Put(Datasets, dataset); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := s.APIClient()
+
+	firstPage, err := c.GetDatasets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	datasets, err := c.GetAllDatasets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*firstPage) != 2 || len(datasets) != 5 {
+		t.Errorf("got %d datasets on the first page and %d in total, expected 2 and 5", len(*firstPage), len(datasets))
+	}
+	holders, err := c.GetDatasetsByAlias("latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 1 || holders[0].ID != "dataset-4" {
+		t.Errorf("expected dataset-4 to hold the alias, got %v", holders)
+	}
+}
+
+func TestRolePermissions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.APIClient()
+
+	action, err := c.CreateAction(api.CreateAction{Name: "read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource, err := c.CreateResource(api.CreateResource{Type: "dataset", Value: "*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	permission, err := c.CreatePermission(api.CreatePermission{Name: "read-datasets", Action: action.ID, Resource: resource.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if permission.Action.Name != "read" || permission.Resource.Type != "dataset" {
+		t.Errorf("permission does not reference its action and resource: %+v", permission)
+	}
+	role, err := c.CreateRole(api.CreateRole{Name: "reader"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.CreateRolePermissions(api.CreateRolePermissions{RoleID: role.ID, Permissions: []int{permission.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	role, err = c.GetRole(strconv.Itoa(role.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(role.Permissions) != 1 || role.Permissions[0].ID != permission.ID {
+		t.Errorf("unexpected role permissions: %+v", role.Permissions)
+	}
+
+	_, err = c.CreatePermission(api.CreatePermission{Name: "broken", Action: 999, Resource: resource.ID})
+	if appError, ok := err.(api.AppError); !ok || appError.Code != api.UnprocessableEntityCode {
+		t.Errorf("expected an unprocessable entity AppError, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.APIClient()
+
+	s.InjectFault(Fault{Method: http.MethodGet, Path: Workspaces, Status: http.StatusInternalServerError, Times: 1})
+	if _, err := c.GetWorkspaces(); err == nil {
+		t.Error("expected the injected error")
+	}
+	if _, err := c.GetWorkspaces(); err != nil {
+		t.Errorf("the fault should only apply once: %v", err)
+	}
+
+	s.InjectFault(Fault{Path: Dataviews, Status: http.StatusUnprocessableEntity})
+	_, err := c.GetDataviews()
+	if appError, ok := err.(api.AppError); !ok || appError.Code != api.UnprocessableEntityCode {
+		t.Errorf("expected an unprocessable entity AppError, got %v", err)
+	}
+	s.ClearFaults()
+
+	s.InjectFault(Fault{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	if _, err := c.GetDataviews(); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Error("expected the injected latency")
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c, _ := api.NewClient(s.URL, "wrong")
+
+	_, err := c.GetDatasets()
+	if appError, ok := err.(api.AppError); !ok || appError.Code != api.UnauthorizedCode {
+		t.Errorf("expected an unauthorized AppError, got %v", err)
+	}
+}
+
+func TestWorkspaceWithoutID(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	req, err := http.NewRequest(http.MethodPost, s.URL+"/"+Workspaces, strings.NewReader(`{"name":"My Workspace","public":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+Token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		t.Fatalf("unexpected status %d", res.StatusCode)
+	}
+
+	workspace, err := s.APIClient().GetWorkspace(api.WorkspaceID("My Workspace", true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workspace.ID != "my_workspace-public" {
+		t.Errorf("unexpected workspace ID %q", workspace.ID)
+	}
+}
+
+func getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+Token)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,90 @@
+package api
+
+import "encoding/json"
+
+// Nullable is an optional attribute of a request body. A nil Nullable is
+// omitted by omitempty, a null one is sent as null to clear the attribute on
+// the server and any other holds a value.
+type Nullable[T any] map[bool]T
+
+// NewNullable returns a Nullable holding v.
+func NewNullable[T any](v T) Nullable[T] {
+	return Nullable[T]{true: v}
+}
+
+// NewNull returns a Nullable sent as null.
+func NewNull[T any]() Nullable[T] {
+	var zero T
+	return Nullable[T]{false: zero}
+}
+
+// NullableString returns s, or null when s is empty.
+func NullableString(s string) Nullable[string] {
+	if s == "" {
+		return NewNull[string]()
+	}
+	return NewNullable(s)
+}
+
+// OptionalString returns s, or an unset Nullable when s is empty.
+func OptionalString(s string) Nullable[string] {
+	if s == "" {
+		return nil
+	}
+	return NewNullable(s)
+}
+
+// IsNull reports whether the attribute is sent as null.
+func (n Nullable[T]) IsNull() bool {
+	_, ok := n[false]
+	return ok
+}
+
+// Get returns the value and whether one is set.
+func (n Nullable[T]) Get() (T, bool) {
+	v, ok := n[true]
+	return v, ok
+}
+
+// Value returns the value, or the zero value when unset or null.
+func (n Nullable[T]) Value() T {
+	return n[true]
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.IsNull() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n[true])
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*n = NewNull[T]()
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*n = NewNullable(v)
+	return nil
+}
+
+func (c DatasetConfiguration) MarshalJSON() ([]byte, error) {
+	type datasetConfiguration DatasetConfiguration
+	body, err := json.Marshal(datasetConfiguration(c))
+	if err != nil || len(c.NullFields) == 0 {
+		return body, err
+	}
+	obj := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, err
+	}
+	for _, key := range c.NullFields {
+		if _, ok := obj[key]; !ok {
+			obj[key] = json.RawMessage("null")
+		}
+	}
+	return json.Marshal(obj)
+}
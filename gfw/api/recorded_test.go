@@ -0,0 +1,146 @@
+package api_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/replay"
+)
+
+// The recorded tests decode responses recorded in testdata/recorded, rejecting
+// unknown fields. The committed recordings were made against the fake API, so
+// they only check the client against the shapes served by the fake. Run make
+// record with GFW_URL and GFW_TOKEN set to record the responses of a real API
+// instead.
+
+const replayHost = "https://gateway.api.globalfishingwatch.org/v3"
+
+func recordedClient(t *testing.T, name string) *api.GFWClient {
+	file := filepath.Join("testdata", "recorded", name+".json")
+	host, token, mode := replayHost, "", replay.Replay
+	if os.Getenv("GFW_RECORD") != "" {
+		host, token, mode = os.Getenv("GFW_URL"), os.Getenv("GFW_TOKEN"), replay.Record
+	}
+	transport, err := replay.NewTransport(mode, file, host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if mode == replay.Record {
+			if err := transport.Save(); err != nil {
+				t.Error(err)
+			}
+			return
+		}
+		for _, interaction := range transport.Unused() {
+			t.Errorf("recorded request not made: %s %s", interaction.Method, interaction.Path)
+		}
+	})
+
+	client, err := api.NewClient(host, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient = transport.Client()
+	client.DisallowUnknownFields = true
+	return client
+}
+
+func TestRecordedDatasets(t *testing.T) {
+	client := recordedClient(t, "datasets")
+	datasets, err := client.GetDatasets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*datasets) == 0 {
+		t.Fatal("no datasets listed")
+	}
+	dataset, err := client.GetDataset((*datasets)[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataset.ID != (*datasets)[0].ID {
+		t.Errorf("got dataset %q, expected %q", dataset.ID, (*datasets)[0].ID)
+	}
+	testRecordedNotFound(t, func() error {
+		_, err := client.GetDataset("missing-dataset:v1")
+		return err
+	})
+}
+
+func TestRecordedDataviews(t *testing.T) {
+	client := recordedClient(t, "dataviews")
+	dataviews, err := client.GetDataviews()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*dataviews) == 0 {
+		t.Fatal("no dataviews listed")
+	}
+	dataview, err := client.GetDataview(strconv.Itoa((*dataviews)[0].ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dataview.ID != (*dataviews)[0].ID {
+		t.Errorf("got dataview %d, expected %d", dataview.ID, (*dataviews)[0].ID)
+	}
+	testRecordedNotFound(t, func() error {
+		_, err := client.GetDataview("0")
+		return err
+	})
+}
+
+func TestRecordedWorkspaces(t *testing.T) {
+	client := recordedClient(t, "workspaces")
+	workspaces, err := client.GetWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*workspaces) == 0 {
+		t.Fatal("no workspaces listed")
+	}
+	workspace, err := client.GetWorkspace((*workspaces)[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workspace.ID != (*workspaces)[0].ID {
+		t.Errorf("got workspace %q, expected %q", workspace.ID, (*workspaces)[0].ID)
+	}
+	testRecordedNotFound(t, func() error {
+		_, err := client.GetWorkspace("missing-workspace")
+		return err
+	})
+}
+
+func testRecordedNotFound(t *testing.T, get func() error) {
+	err := get()
+	appError, ok := err.(api.AppError)
+	if !ok || appError.Code != api.NotFoundCode {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestRecordedUnknownFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dataset.json")
+	fixture := `[{"method": "GET", "path": "/datasets/public-eez-areas?includes%5B0%5D=BACKEND_CONFIGURATION", "status": 200,
+		"response": {"id": "public-eez-areas", "configuration": {"contextLayerV1": {"idProperty": "mrgid", "unknownProperty": "polygon"}}}}]`
+	if err := os.WriteFile(file, []byte(fixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	transport, err := replay.NewTransport(replay.Replay, file, replayHost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := api.NewClient(replayHost, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient = transport.Client()
+	client.DisallowUnknownFields = true
+	if _, err := client.GetDataset("public-eez-areas"); err == nil {
+		t.Error("expected an error for the unknown field unknownProperty")
+	}
+}
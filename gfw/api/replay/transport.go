@@ -0,0 +1,227 @@
+// Package replay implements an http.RoundTripper that records the responses of
+// the GFW API to fixture files and replays them, so api.GFWClient can be
+// tested against real response shapes without credentials or network.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type Mode int
+
+const (
+	// Replay answers requests from the fixture file, failing on requests
+	// that were not recorded.
+	Replay Mode = iota
+	// Record sends requests to the API and keeps the responses, written to
+	// the fixture file by Save.
+	Record
+)
+
+// Keys whose values are replaced before a response is saved.
+var SCRUBBED_KEYS = map[string]bool{
+	"password":  true,
+	"token":     true,
+	"email":     true,
+	"ownerId":   true,
+	"createdBy": true,
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// Interaction is a recorded request and its response. Paths are relative to
+// the base URL of the client, so fixtures do not depend on the host.
+type Interaction struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response,omitempty"`
+	// Text is the response when it is not JSON.
+	Text string `json:"text,omitempty"`
+}
+
+type Transport struct {
+	Mode Mode
+	// File is the fixture file, one per endpoint.
+	File string
+	// BaseURL is the host URL of the client, stripped from recorded paths.
+	BaseURL string
+	// Next sends the requests when recording, http.DefaultTransport if nil.
+	Next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewTransport returns a transport for the fixture file. When replaying the
+// file is loaded, it must exist.
+func NewTransport(mode Mode, file string, baseURL string) (*Transport, error) {
+	t := &Transport{Mode: mode, File: file, BaseURL: baseURL}
+	if mode == Record {
+		return t, nil
+	}
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &t.interactions); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %v", file, err)
+	}
+	t.used = make([]bool, len(t.interactions))
+	return t, nil
+}
+
+// Client returns an http.Client using the transport.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := t.relativePath(req.URL)
+	if t.Mode == Record {
+		return t.record(req, path)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.interactions {
+		if t.used[i] || interaction.Method != req.Method || interaction.Path != path {
+			continue
+		}
+		t.used[i] = true
+		body := []byte(interaction.Response)
+		if interaction.Text != "" {
+			body = []byte(interaction.Text)
+		}
+		return &http.Response{
+			StatusCode: interaction.Status,
+			Status:     fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no response recorded in %s for %s %s", t.File, req.Method, path)
+}
+
+func (t *Transport) record(req *http.Request, path string) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{Method: req.Method, Path: path, Status: res.StatusCode}
+	if json.Valid(body) {
+		scrubbed, err := Scrub(body)
+		if err != nil {
+			return nil, fmt.Errorf("response of %s %s can not be scrubbed: %v", req.Method, path, err)
+		}
+		interaction.Response = scrubbed
+	} else {
+		interaction.Text = emailPattern.ReplaceAllString(string(body), "user@example.com")
+	}
+	t.mu.Lock()
+	t.interactions = append(t.interactions, interaction)
+	t.mu.Unlock()
+	return res, nil
+}
+
+// Save writes the recorded interactions to the fixture file.
+func (t *Transport) Save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	body, err := json.MarshalIndent(t.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.File), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.File, append(body, '\n'), 0644)
+}
+
+// Unused returns the recorded interactions that were not replayed.
+func (t *Transport) Unused() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range t.interactions {
+		if !t.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// relativePath returns the path and query of a request relative to the base
+// URL, without the cache parameter added to every request.
+func (t *Transport) relativePath(u *url.URL) string {
+	path := u.Path
+	if base, err := url.Parse(t.BaseURL); err == nil {
+		path = strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/"))
+	}
+	query := u.Query()
+	query.Del("cache")
+	if len(query) > 0 {
+		path = fmt.Sprintf("%s?%s", path, query.Encode())
+	}
+	return path
+}
+
+// Scrub replaces the values of SCRUBBED_KEYS and the email addresses in a JSON
+// body.
+func Scrub(body []byte) (json.RawMessage, error) {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(scrubValue(value))
+}
+
+func scrubValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, e := range v {
+			if !SCRUBBED_KEYS[key] {
+				v[key] = scrubValue(e)
+				continue
+			}
+			switch e.(type) {
+			case string:
+				v[key] = "REDACTED"
+			case float64:
+				v[key] = 0
+			}
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = scrubValue(e)
+		}
+		return v
+	case string:
+		return emailPattern.ReplaceAllString(v, "user@example.com")
+	}
+	return value
+}
@@ -0,0 +1,67 @@
+package replay
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cache") != "false" {
+			t.Errorf("cache parameter not sent: %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"public-eez-areas","ownerId":42,"password":"secret","description":"Contact jane.doe@example.org"}`))
+	}))
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "datasets.json")
+	recorder, err := NewTransport(Record, file, server.URL+"/v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := get(t, recorder.Client(), server.URL+"/v3/datasets/public-eez-areas?cache=false")
+	if !strings.Contains(recorded, "secret") {
+		t.Errorf("the recorded response must not be scrubbed, got %s", recorded)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	player, err := NewTransport(Replay, file, "https://gateway.api.globalfishingwatch.org/v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := get(t, player.Client(), "https://gateway.api.globalfishingwatch.org/v3/datasets/public-eez-areas?cache=false")
+	for _, secret := range []string{"secret", "42", "jane.doe"} {
+		if strings.Contains(replayed, secret) {
+			t.Errorf("%q not scrubbed from %s", secret, replayed)
+		}
+	}
+	if !strings.Contains(replayed, "public-eez-areas") {
+		t.Errorf("unexpected response %s", replayed)
+	}
+	if len(player.Unused()) != 0 {
+		t.Errorf("interactions not replayed: %v", player.Unused())
+	}
+	if _, err := player.Client().Get("https://gateway.api.globalfishingwatch.org/v3/datasets/public-eez-areas"); err == nil {
+		t.Error("a response must be replayed only once")
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
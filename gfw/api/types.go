@@ -204,7 +204,7 @@ type FourwingsV1Config struct {
 	Intervals                    []string                     `json:"intervals,omitempty"`
 	TTL                          int                          `json:"ttl"`
 	Max                          *float64                     `json:"max,omitempty"`
-	Min                          *float64                     `json:"min"`
+	Min                          *float64                     `json:"min,omitempty"`
 	TileScale                    *float64                     `json:"tileScale,omitempty"`
 	TileOffset                   *float64                     `json:"tileOffset,omitempty"`
 	InternalScale                *float64                     `json:"internalScale,omitempty"`
@@ -232,7 +232,7 @@ type FrontendConfig struct {
 	MaxZoom            int      `json:"maxZoom,omitempty"`
 	Translate          bool     `json:"translate,omitempty"`
 	Max                *float64 `json:"max,omitempty"`
-	Min                *float64 `json:"min"`
+	Min                *float64 `json:"min,omitempty"`
 	DisableInteraction bool     `json:"disableInteraction,omitempty"`
 	Latitude           string   `json:"latitude,omitempty"`
 	Longitude          string   `json:"longitude,omitempty"`
@@ -305,6 +305,9 @@ type DatasetConfiguration struct {
 	BulkDownloadV1         *BulkDownloadV1Config         `json:"bulkDownloadV1,omitempty"`
 	DataDownloadV1         *DataDownloadV1Config         `json:"dataDownloadV1,omitempty"`
 	ThumbnailsV1           *ThumbnailsV1Config           `json:"thumbnailsV1,omitempty"`
+	// NullFields lists the JSON keys of the blocks sent as null to remove
+	// them from the dataset.
+	NullFields []string `json:"-"`
 }
 type RelatedDataset struct {
 	ID   string `json:"id"`
@@ -328,21 +331,24 @@ type Dataset struct {
 	RelatedDatasets []RelatedDataset      `json:"relatedDatasets"`
 	Filters         *DatasetFilters       `json:"filters,omitempty"`
 	Documentation   *DatasetDocumentation `json:"documentation,omitempty"`
+	// UnknownFields are the JSON paths of the response not modelled above,
+	// set when the client reports them.
+	UnknownFields []string `json:"-"`
 }
 
 type CreateDataset struct {
 	ID              string                `json:"id,omitempty"`
 	Name            string                `json:"name,omitempty"`
-	Description     string                `json:"description,omitempty"`
+	Description     Nullable[string]      `json:"description,omitempty"`
 	Type            string                `json:"type,omitempty"`
 	Alias           []string              `json:"alias,omitempty"`
-	StartDate       string                `json:"startDate,omitempty"`
-	EndDate         string                `json:"endDate,omitempty"`
-	Unit            string                `json:"unit,omitempty"`
+	StartDate       Nullable[string]      `json:"startDate,omitempty"`
+	EndDate         Nullable[string]      `json:"endDate,omitempty"`
+	Unit            Nullable[string]      `json:"unit,omitempty"`
 	Status          string                `json:"status,omitempty"`
 	Category        string                `json:"category,omitempty"`
-	Subcategory     string                `json:"subcategory,omitempty"`
-	Source          string                `json:"source,omitempty"`
+	Subcategory     Nullable[string]      `json:"subcategory,omitempty"`
+	Source          Nullable[string]      `json:"source,omitempty"`
 	Configuration   *DatasetConfiguration `json:"configuration,omitempty"`
 	RelatedDatasets []RelatedDataset      `json:"relatedDatasets,omitempty"`
 	Filters         *DatasetFilters       `json:"filters,omitempty"`
@@ -383,13 +389,14 @@ type Dataview struct {
 	EventsConfig   *map[string]interface{}   `json:"eventsConfig,omitempty"`
 	FiltersConfig  *map[string]interface{}   `json:"filtersConfig,omitempty"`
 	DatasetsConfig *[]map[string]interface{} `json:"datasetsConfig,omitempty"`
+	UnknownFields  []string                  `json:"-"`
 }
 
 type CreateDataview struct {
 	Name           string                    `json:"name,omitempty"`
 	Slug           string                    `json:"slug,omitempty"`
-	Description    string                    `json:"description,omitempty"`
-	Category       string                    `json:"category,omitempty"`
+	Description    Nullable[string]          `json:"description,omitempty"`
+	Category       Nullable[string]          `json:"category,omitempty"`
 	App            string                    `json:"app,omitempty"`
 	CreatedAt      string                    `json:"createdAt,omitempty"`
 	UpdatedAt      string                    `json:"updatedAt,omitempty"`
@@ -428,24 +435,38 @@ type Workspace struct {
 	State             *map[string]interface{}      `json:"state"`
 	DataviewInstances *[]WorkspaceDataviewInstance `json:"dataviewInstances"`
 	CreatedAt         string                       `json:"createdAt"`
+	ViewAccess        string                       `json:"viewAccess"`
+	EditAccess        string                       `json:"editAccess"`
+	EditorUserGroups  []int                        `json:"editorUserGroups"`
+	UnknownFields     []string                     `json:"-"`
 }
 
 type CreateWorkspace struct {
 	ID                string                       `json:"id,omitempty"`
 	Name              string                       `json:"name,omitempty"`
-	Description       string                       `json:"description,omitempty"`
-	Category          string                       `json:"category,omitempty"`
+	Description       Nullable[string]             `json:"description,omitempty"`
+	Category          Nullable[string]             `json:"category,omitempty"`
 	App               string                       `json:"app,omitempty"`
-	Aoi               string                       `json:"aoi,omitempty"`
-	StartAt           string                       `json:"startAt,omitempty"`
-	EndAt             string                       `json:"endAt,omitempty"`
-	Public            bool                         `json:"public,omitempty"`
-	Viewport          *WorkspaceViewport           `json:"viewport,omitempty"`
+	Aoi               Nullable[string]             `json:"aoi,omitempty"`
+	StartAt           Nullable[string]             `json:"startAt,omitempty"`
+	EndAt             Nullable[string]             `json:"endAt,omitempty"`
+	Public            *bool                        `json:"public,omitempty"`
+	Viewport          Nullable[WorkspaceViewport]  `json:"viewport,omitempty"`
 	State             *map[string]interface{}      `json:"state,omitempty"`
 	Dataviews         []int                        `json:"dataviews,omitempty"`
 	DataviewInstances *[]WorkspaceDataviewInstance `json:"dataviewInstances,omitempty"`
+	ViewAccess        string                       `json:"viewAccess,omitempty"`
+	EditAccess        string                       `json:"editAccess,omitempty"`
+	// Password is required when view or edit access is password, it is
+	// never returned by the API.
+	Password         Nullable[string] `json:"password,omitempty"`
+	EditorUserGroups *[]int           `json:"editorUserGroups,omitempty"`
 }
 
+// Patch is the body of a partial update. Keys set to nil are sent as null to
+// clear the attribute on the server.
+type Patch map[string]interface{}
+
 type Pagination[T any] struct {
 	Total      int                    `json:"total"`
 	Limit      *int                   `json:"limit"`
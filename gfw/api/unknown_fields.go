@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownFields returns the JSON paths of the attributes of body that v does
+// not model, for example configuration.fourwingsV1.newThing. Free-form maps
+// accept any attribute.
+func UnknownFields(body []byte, v interface{}) ([]string, error) {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+	paths := unknownFields(value, reflect.TypeOf(v), "")
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func unknownFields(value interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// Nullable values are maps keyed by bool.
+		if t.Kind() == reflect.Map && t.Key().Kind() == reflect.Bool {
+			return unknownFields(value, t.Elem(), path)
+		}
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		var paths []string
+		for key, e := range v {
+			field, ok := jsonField(t, key)
+			if !ok {
+				paths = append(paths, JoinPath(path, key))
+				continue
+			}
+			paths = append(paths, unknownFields(e, field.Type, JoinPath(path, key))...)
+		}
+		return paths
+	case []interface{}:
+		if t.Kind() == reflect.Map && t.Key().Kind() == reflect.Bool {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		var paths []string
+		for i, e := range v {
+			paths = append(paths, unknownFields(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return paths
+	}
+	return nil
+}
+
+// jsonField returns the field of struct t decoded from key. As in
+// encoding/json, names match case insensitively and embedded structs are
+// flattened.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			if embedded, ok := jsonField(field.Type, key); ok {
+				return embedded, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// JoinPath appends key to a dotted attribute path.
+func JoinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
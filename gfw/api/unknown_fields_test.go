@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+)
+
+func TestUnknownFields(t *testing.T) {
+	body := []byte(`{
+		"id": "public-sst:v1",
+		"schemaVersion": 2,
+		"configuration": {"fourwingsV1": {"max": 30, "newThing": true}},
+		"filters": {"fourwings": [{"id": "flag"}, {"id": "gear", "newFilter": 1}]},
+		"relatedDatasets": [{"id": "public-eez-areas", "type": "context-layer:v1"}]
+	}`)
+	paths, err := api.UnknownFields(body, &api.Dataset{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"configuration.fourwingsV1.newThing", "filters.fourwings[1].newFilter", "schemaVersion"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("got %v, expected %v", paths, expected)
+	}
+
+	// Free-form maps and nullable values accept their attributes.
+	body = []byte(`{"name": "Fishing", "description": "All", "state": {"anything": 1}, "viewport": {"zoom": 3, "pitch": 10}}`)
+	paths, err = api.UnknownFields(body, &api.CreateWorkspace{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(paths, []string{"viewport.pitch"}) {
+		t.Errorf("got %v, expected [viewport.pitch]", paths)
+	}
+}
+
+func TestReportUnknownFields(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+	id, err := s.Put(fake.Datasets, map[string]interface{}{
+		"id":            "public-sst:v1",
+		"configuration": map[string]interface{}{"fourwingsV1": map[string]interface{}{"newThing": true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := s.APIClient()
+	dataset, err := client.GetDataset(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dataset.UnknownFields) != 0 {
+		t.Errorf("unknown fields reported while disabled: %v", dataset.UnknownFields)
+	}
+
+	client.ReportUnknownFields = true
+	dataset, err = client.GetDataset(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dataset.UnknownFields, []string{"configuration.fourwingsV1.newThing"}) {
+		t.Errorf("got %v", dataset.UnknownFields)
+	}
+}
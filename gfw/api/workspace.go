@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/iancoleman/strcase"
@@ -22,7 +23,7 @@ func (c *GFWClient) GetWorkspaces() (*[]Workspace, error) {
 	}
 
 	workspaces := Pagination[Workspace]{}
-	err = json.Unmarshal(body, &workspaces)
+	err = c.unmarshal(body, &workspaces)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +31,12 @@ func (c *GFWClient) GetWorkspaces() (*[]Workspace, error) {
 	return &workspaces.Entries, nil
 }
 
+// GetAllWorkspaces returns every workspace, GetWorkspaces only reads the first
+// page.
+func (c *GFWClient) GetAllWorkspaces() ([]Workspace, error) {
+	return getAllPages[Workspace](c, WORKSPACE_PATH, url.Values{})
+}
+
 func (c *GFWClient) GetWorkspace(id string) (*Workspace, error) {
 	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", c.HostURL, WORKSPACE_PATH, id), nil)
 	if err != nil {
@@ -41,11 +48,21 @@ func (c *GFWClient) GetWorkspace(id string) (*Workspace, error) {
 	}
 
 	workspace := Workspace{}
-	err = json.Unmarshal(body, &workspace)
+	err = c.unmarshal(body, &workspace)
 	if err != nil {
 		return nil, err
 	}
-	workspace.Public = strings.HasSuffix(workspace.ID, "-public")
+	// Responses without public are read as before the API returned it, from
+	// the -public suffix of the ID.
+	public := struct {
+		Public *bool `json:"public"`
+	}{}
+	if err := json.Unmarshal(body, &public); err != nil {
+		return nil, err
+	}
+	if public.Public == nil {
+		workspace.Public = strings.HasSuffix(workspace.ID, "-public")
+	}
 	return &workspace, nil
 }
 
@@ -62,7 +79,7 @@ func (c *GFWClient) DeleteWorkspace(id string) (*Workspace, error) {
 		return nil, nil
 	}
 	workspace := Workspace{}
-	err = json.Unmarshal(body, &workspace)
+	err = c.unmarshal(body, &workspace)
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +87,11 @@ func (c *GFWClient) DeleteWorkspace(id string) (*Workspace, error) {
 	return &workspace, nil
 }
 
-func (c *GFWClient) UpdateWorkspace(id string, workspace CreateWorkspace) error {
+// UpdateWorkspace sends a partial update, attributes set to nil in the patch
+// are cleared on the server.
+func (c *GFWClient) UpdateWorkspace(id string, patch Patch) error {
 
-	bodyReq, err := json.Marshal(workspace)
+	bodyReq, err := json.Marshal(patch)
 	if err != nil {
 		return err
 	}
@@ -88,19 +107,15 @@ func (c *GFWClient) UpdateWorkspace(id string, workspace CreateWorkspace) error
 }
 
 func (c *GFWClient) CreateWorkspace(workspace CreateWorkspace) (*Workspace, error) {
-	id := workspace.ID
-	if id == "" {
-		id = strcase.ToSnake(workspace.Name)
-		if workspace.Public {
-			id = fmt.Sprintf("%s-public", id)
-		}
+	if workspace.ID == "" {
+		workspace.ID = WorkspaceID(workspace.Name, workspace.Public != nil && *workspace.Public)
 	}
-	exists, err := c.checkExistWorkspace(id)
+	exists, err := c.checkExistWorkspace(workspace.ID)
 	if err != nil {
 		return nil, err
 	}
 	if exists != nil {
-		return exists, nil
+		return nil, fmt.Errorf("workspace %q already exists, import it to manage it", workspace.ID)
 	}
 
 	bodyReq, err := json.Marshal(workspace)
@@ -119,7 +134,7 @@ func (c *GFWClient) CreateWorkspace(workspace CreateWorkspace) (*Workspace, erro
 	}
 
 	newWorkspace := Workspace{}
-	err = json.Unmarshal(body, &newWorkspace)
+	err = c.unmarshal(body, &newWorkspace)
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +142,81 @@ func (c *GFWClient) CreateWorkspace(workspace CreateWorkspace) (*Workspace, erro
 	return &newWorkspace, nil
 }
 
+// GetWorkspaceDataviewInstance returns a single dataview instance of a
+// workspace, or a not found error when the workspace does not hold it.
+func (c *GFWClient) GetWorkspaceDataviewInstance(workspaceId string, id string) (*WorkspaceDataviewInstance, error) {
+	workspace, err := c.GetWorkspace(workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if workspace.DataviewInstances != nil {
+		for _, instance := range *workspace.DataviewInstances {
+			if instance.ID == id {
+				return &instance, nil
+			}
+		}
+	}
+	return nil, *NewNotFoundStandard(fmt.Sprintf("dataview instance %s not found in workspace %s", id, workspaceId))
+}
+
+// SetWorkspaceDataviewInstance replaces the dataview instance with the same
+// ID, or appends it, leaving the other instances of the workspace untouched.
+// The API has no endpoint per instance, so the whole list is read and patched
+// back, callers serialise the calls on the same workspace.
+func (c *GFWClient) SetWorkspaceDataviewInstance(workspaceId string, instance WorkspaceDataviewInstance) error {
+	workspace, err := c.GetWorkspace(workspaceId)
+	if err != nil {
+		return err
+	}
+	instances := []WorkspaceDataviewInstance{}
+	replaced := false
+	if workspace.DataviewInstances != nil {
+		for _, current := range *workspace.DataviewInstances {
+			if current.ID == instance.ID {
+				current = instance
+				replaced = true
+			}
+			instances = append(instances, current)
+		}
+	}
+	if !replaced {
+		instances = append(instances, instance)
+	}
+	return c.UpdateWorkspace(workspaceId, Patch{"dataviewInstances": instances})
+}
+
+// RemoveWorkspaceDataviewInstance removes a dataview instance from the
+// workspace, leaving the other instances untouched.
+func (c *GFWClient) RemoveWorkspaceDataviewInstance(workspaceId string, id string) error {
+	workspace, err := c.GetWorkspace(workspaceId)
+	if err != nil {
+		return err
+	}
+	if workspace.DataviewInstances == nil {
+		return nil
+	}
+	instances := []WorkspaceDataviewInstance{}
+	for _, current := range *workspace.DataviewInstances {
+		if current.ID != id {
+			instances = append(instances, current)
+		}
+	}
+	if len(instances) == len(*workspace.DataviewInstances) {
+		return nil
+	}
+	return c.UpdateWorkspace(workspaceId, Patch{"dataviewInstances": instances})
+}
+
+// WorkspaceID returns the ID given to a workspace created without one: the
+// snake case name, with a -public suffix for public workspaces.
+func WorkspaceID(name string, public bool) string {
+	id := strcase.ToSnake(name)
+	if public {
+		id = fmt.Sprintf("%s-public", id)
+	}
+	return id
+}
+
 func (c *GFWClient) checkExistWorkspace(id string) (*Workspace, error) {
 	exists, err := c.GetWorkspace(id)
 	if err != nil {
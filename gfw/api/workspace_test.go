@@ -0,0 +1,56 @@
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+)
+
+// Workspaces without public in the response are public when their ID has the
+// -public suffix, the value returned by the API wins otherwise.
+func TestGetWorkspacePublic(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+	for _, w := range []map[string]interface{}{
+		{"id": "legacy-public", "name": "Legacy"},
+		{"id": "legacy", "name": "Legacy"},
+		{"id": "private-public", "name": "Private", "public": false},
+		{"id": "shared", "name": "Shared", "public": true},
+	} {
+		if _, err := s.Put(fake.Workspaces, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := s.APIClient()
+	for id, expected := range map[string]bool{
+		"legacy-public":  true,
+		"legacy":         false,
+		"private-public": false,
+		"shared":         true,
+	} {
+		workspace, err := client.GetWorkspace(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if workspace.Public != expected {
+			t.Errorf("workspace %s: got public %v, expected %v", id, workspace.Public, expected)
+		}
+	}
+}
+
+func TestCreateWorkspaceExists(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+	if _, err := s.Put(fake.Workspaces, map[string]interface{}{"id": "fishing", "name": "Declared by hand"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := s.APIClient().CreateWorkspace(api.CreateWorkspace{Name: "Fishing"})
+	if err == nil || !strings.Contains(err.Error(), `workspace "fishing" already exists, import it`) {
+		t.Fatalf("expected an already exists error, got %v", err)
+	}
+	if workspace, _ := s.Get(fake.Workspaces, "fishing"); workspace["name"] != "Declared by hand" {
+		t.Errorf("existing workspace was changed: %v", workspace)
+	}
+}
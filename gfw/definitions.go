@@ -0,0 +1,377 @@
+package gfw
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/iancoleman/strcase"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DEFINITION_DATASET  = "dataset"
+	DEFINITION_DATAVIEW = "dataview"
+)
+
+// DefinitionProblem is an error or warning found in a dataset or dataview
+// definition. Path is the JSON path of the attribute, empty for the whole
+// definition.
+type DefinitionProblem struct {
+	File     string `json:"file"`
+	Kind     string `json:"kind,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Path     string `json:"path"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type definition struct {
+	file     string
+	kind     string
+	id       string
+	body     []byte
+	dataset  *api.CreateDataset
+	dataview *api.CreateDataview
+}
+
+// ValidateDefinitions checks the CreateDataset and CreateDataview bodies of
+// the JSON or YAML files as the provider does at plan time, without calling
+// the API: the schema validation, the enums and the dataview filters, which
+// are checked against the datasets defined in the same files. A file holds a
+// definition or a list of them.
+func ValidateDefinitions(files []string, enumsFile string) ([]DefinitionProblem, error) {
+	enums := defaultEnums()
+	if enumsFile != "" {
+		file, err := loadEnumsFile(enumsFile)
+		if err != nil {
+			return nil, err
+		}
+		enums.override(file)
+	}
+
+	var problems []DefinitionProblem
+	var definitions []*definition
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseDefinitions(file, body)
+		if err != nil {
+			problems = append(problems, DefinitionProblem{File: file, Severity: "error", Message: err.Error()})
+			continue
+		}
+		definitions = append(definitions, parsed...)
+	}
+
+	datasets := map[string]*api.Dataset{}
+	for _, d := range definitions {
+		problems = append(problems, d.decode()...)
+		if d.dataset != nil {
+			datasets[d.dataset.ID] = &api.Dataset{ID: d.dataset.ID, Filters: d.dataset.Filters}
+		}
+	}
+	for _, d := range definitions {
+		switch {
+		case d.dataset != nil:
+			problems = append(problems, d.validate(resourceDataset(), datasetDefinitionToSchema(*d.dataset), enums, nil)...)
+		case d.dataview != nil:
+			problems = append(problems, d.validate(resourceDataview(), dataviewDefinitionToSchema(*d.dataview), enums, datasets)...)
+		}
+	}
+	return problems, nil
+}
+
+// parseDefinitions reads the definitions of a file, YAML when its extension
+// is .yaml or .yml and JSON otherwise.
+func parseDefinitions(file string, body []byte) ([]*definition, error) {
+	var value interface{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(body, &value); err != nil {
+			return nil, err
+		}
+	default:
+		if err := json.Unmarshal(body, &value); err != nil {
+			return nil, err
+		}
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	var definitions []*definition
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("definition %d is not an object", i)
+		}
+		// The body is encoded again, YAML is decoded as JSON afterwards.
+		body, err := json.Marshal(object)
+		if err != nil {
+			return nil, err
+		}
+		d := &definition{file: file, kind: DEFINITION_DATASET, body: body}
+		if _, ok := object["slug"]; ok {
+			d.kind = DEFINITION_DATAVIEW
+		} else if _, ok := object["config"]; ok {
+			d.kind = DEFINITION_DATAVIEW
+		}
+		definitions = append(definitions, d)
+	}
+	return definitions, nil
+}
+
+// decode decodes the definition into its API type, reporting attributes the
+// type does not have.
+func (d *definition) decode() []DefinitionProblem {
+	var v interface{} = &api.CreateDataset{}
+	if d.kind == DEFINITION_DATAVIEW {
+		v = &api.CreateDataview{}
+	}
+	if err := json.Unmarshal(d.body, v); err != nil {
+		return []DefinitionProblem{d.problem("", "error", err.Error())}
+	}
+	switch value := v.(type) {
+	case *api.CreateDataset:
+		d.dataset, d.id = value, value.ID
+	case *api.CreateDataview:
+		d.dataview, d.id = value, value.Slug
+	}
+
+	var problems []DefinitionProblem
+	paths, _ := api.UnknownFields(d.body, v)
+	for _, path := range paths {
+		problems = append(problems, d.problem(path, "error", "unknown attribute"))
+	}
+	return problems
+}
+
+func (d *definition) validate(resource *schema.Resource, raw map[string]interface{}, enums Enums, datasets map[string]*api.Dataset) []DefinitionProblem {
+	var problems []DefinitionProblem
+	for _, diagnostic := range resource.Validate(terraform.NewResourceConfigRaw(raw)) {
+		problems = append(problems, d.diagnosticProblem(resource, diagnostic))
+	}
+
+	state := resource.Data(nil)
+	for key, value := range raw {
+		state.Set(key, value)
+	}
+	values := datasetEnumValues
+	if d.kind == DEFINITION_DATAVIEW {
+		values = dataviewEnumValues
+	}
+	for _, v := range values(state) {
+		for _, diagnostic := range checkEnumValues(enums, []enumValue{v}) {
+			problems = append(problems, d.problem(definitionJSONPath(resource.Schema, strings.Split(v.Path, ".")), "error", diagnostic.Detail))
+		}
+	}
+
+	if d.kind == DEFINITION_DATAVIEW {
+		referenced := map[string]*api.Dataset{}
+		for _, ref := range dataviewDatasetReferences(state.Get("config").([]interface{})) {
+			if dataset, ok := datasets[ref.ID]; ok {
+				referenced[ref.ID] = dataset
+			}
+		}
+		filters := state.Get("config.0.filter").(*schema.Set).List()
+		if err := validateDataviewFilters(filters, referenced); err != nil {
+			problems = append(problems, d.problem("config.filters", "error", err.Error()))
+		}
+	}
+	return problems
+}
+
+func (d *definition) problem(path, severity, message string) DefinitionProblem {
+	return DefinitionProblem{File: d.file, Kind: d.kind, ID: d.id, Path: path, Severity: severity, Message: message}
+}
+
+func (d *definition) diagnosticProblem(resource *schema.Resource, diagnostic diag.Diagnostic) DefinitionProblem {
+	var segments []string
+	for _, step := range diagnostic.AttributePath {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			segments = append(segments, s.Name)
+		case cty.IndexStep:
+			if s.Key.Type() == cty.Number {
+				i, _ := s.Key.AsBigFloat().Int64()
+				segments = append(segments, strconv.FormatInt(i, 10))
+			}
+		}
+	}
+	severity := "error"
+	if diagnostic.Severity == diag.Warning {
+		severity = "warning"
+	}
+	message := diagnostic.Summary
+	if diagnostic.Detail != "" {
+		message = fmt.Sprintf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+	return d.problem(definitionJSONPath(resource.Schema, segments), severity, message)
+}
+
+// definitionJSONPath turns the path of an attribute of the schema into the
+// path of the definition, where blocks of a single item are objects.
+func definitionJSONPath(s map[string]*schema.Schema, segments []string) string {
+	path := ""
+	var list *schema.Schema
+	for _, segment := range segments {
+		if i, err := strconv.Atoi(segment); err == nil && list != nil {
+			if list.MaxItems != 1 {
+				path = fmt.Sprintf("%s[%d]", path, i)
+			}
+			list = nil
+			continue
+		}
+		list = nil
+		name := strcase.ToLowerCamel(segment)
+		if segment == "dataset_id" {
+			name = "id"
+		}
+		path = api.JoinPath(path, name)
+		attribute, ok := s[segment]
+		if !ok {
+			continue
+		}
+		if attribute.Type == schema.TypeList || attribute.Type == schema.TypeSet {
+			list = attribute
+		}
+		if block, ok := attribute.Elem.(*schema.Resource); ok {
+			s = block.Schema
+		}
+	}
+	return path
+}
+
+// datasetDefinitionToSchema returns the configuration of a gfw_dataset
+// resource creating the dataset.
+func datasetDefinitionToSchema(dataset api.CreateDataset) map[string]interface{} {
+	raw := map[string]interface{}{
+		"dataset_id":  dataset.ID,
+		"name":        dataset.Name,
+		"description": dataset.Description.Value(),
+		"type":        dataset.Type,
+		"alias":       dataset.Alias,
+		"start_date":  dataset.StartDate.Value(),
+		"end_date":    dataset.EndDate.Value(),
+		"unit":        dataset.Unit.Value(),
+		"status":      dataset.Status,
+		"category":    dataset.Category,
+		"subcategory": dataset.Subcategory.Value(),
+		"source":      dataset.Source.Value(),
+	}
+	if dataset.Configuration != nil {
+		raw["configuration"] = []interface{}{flattenDatasetConfiguration(*dataset.Configuration)}
+	}
+	if dataset.Filters != nil {
+		raw["filters"] = []interface{}{flattenDatasetFilters(*dataset.Filters)}
+	}
+	if dataset.Documentation != nil {
+		raw["documentation"] = []interface{}{flattenDatasetDocumentation(*dataset.Documentation)}
+	}
+	if len(dataset.RelatedDatasets) > 0 {
+		raw["related_datasets"] = flattenRelatedDatasets(dataset.RelatedDatasets)
+	}
+	return withoutUnsetAttributes(resourceDataset().Schema, expandableValue(raw).(map[string]interface{}))
+}
+
+// dataviewDefinitionToSchema returns the configuration of a gfw_dataview
+// resource creating the dataview.
+func dataviewDefinitionToSchema(dataview api.CreateDataview) map[string]interface{} {
+	raw := map[string]interface{}{
+		"slug":        dataview.Slug,
+		"name":        dataview.Name,
+		"description": dataview.Description.Value(),
+		"category":    dataview.Category.Value(),
+		"app":         dataview.App,
+	}
+	if dataview.Config != nil {
+		raw["config"] = []interface{}{flattenDataviewConfiguration(*dataview.Config, true, true)}
+	}
+	for key, value := range map[string]*map[string]interface{}{
+		"info_config":    dataview.InfoConfig,
+		"events_config":  dataview.EventsConfig,
+		"filters_config": dataview.FiltersConfig,
+	} {
+		if value != nil {
+			body, _ := json.Marshal(value)
+			raw[key] = string(body)
+		}
+	}
+	if dataview.DatasetsConfig != nil {
+		datasetsConfig := make([]interface{}, len(*dataview.DatasetsConfig))
+		for i, config := range *dataview.DatasetsConfig {
+			body, _ := json.Marshal(config)
+			datasetsConfig[i] = string(body)
+		}
+		raw["datasets_config"] = datasetsConfig
+	}
+	return withoutUnsetAttributes(resourceDataview().Schema, expandableValue(raw).(map[string]interface{}))
+}
+
+// withoutUnsetAttributes removes the optional attributes holding a zero value,
+// the flatten functions set them all while a configuration leaves them out.
+func withoutUnsetAttributes(s map[string]*schema.Schema, raw map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := raw[key]
+		attribute, ok := s[key]
+		if !ok {
+			result[key] = value
+			continue
+		}
+		if block, ok := attribute.Elem.(*schema.Resource); ok {
+			if list, ok := value.([]interface{}); ok {
+				elements := make([]interface{}, 0, len(list))
+				for _, element := range list {
+					fields, _ := element.(map[string]interface{})
+					elements = append(elements, withoutUnsetAttributes(block.Schema, fields))
+				}
+				value = elements
+			}
+		}
+		if !attribute.Required && isZeroDefinitionValue(value) {
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func isZeroDefinitionValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case []float64:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
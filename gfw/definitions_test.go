@@ -0,0 +1,96 @@
+package gfw
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDefinitionDataset = `{
+  "id": "public-sst:v1",
+  "name": "Sea surface temperature",
+  "description": "Sea surface temperature",
+  "type": "4wings:v1",
+  "category": "environment",
+  "subcategory": "water-temperature",
+  "configuration": {"fourwingsV1": {"intervals": ["DAY", "MONTH"], "maxZoom": 12}},
+  "filters": {"fourwings": [{"id": "depth", "type": "number", "min": 0, "max": 100}]}
+}`
+
+const testDefinitionDataview = `
+slug: sea-surface-temperature
+name: Sea surface temperature
+description: Sea surface temperature
+app: fishing-map
+config:
+  type: HEATMAP_ANIMATED
+  datasets: [public-sst:v1]
+  filters:
+    depth: ["10"]
+`
+
+func TestValidateDefinitions(t *testing.T) {
+	problems := validateTestDefinitions(t, map[string]string{
+		"sst.json":      testDefinitionDataset,
+		"dataview.yaml": testDefinitionDataview,
+	})
+	if len(problems) > 0 {
+		t.Fatalf("expected no problems, got %+v", problems)
+	}
+}
+
+func TestValidateDefinitionsProblems(t *testing.T) {
+	dataset := strings.NewReplacer(
+		`"MONTH"`, `"WEEK"`,
+		`"maxZoom": 12`, `"maxZoom": 12, "newThing": true`,
+		`"type": "4wings:v1"`, `"type": "4wings:v2"`,
+	).Replace(testDefinitionDataset)
+	dataview := strings.Replace(testDefinitionDataview, `["10"]`, `["200"]`, 1)
+
+	problems := validateTestDefinitions(t, map[string]string{
+		"sst.json":      dataset,
+		"dataview.yaml": dataview,
+	})
+	expected := map[string]string{
+		"configuration.fourwingsV1.newThing":     "unknown attribute",
+		"configuration.fourwingsV1.intervals[1]": "WEEK",
+		"type":                                   "4wings:v2",
+		"config.filters":                         `value "200" is greater than 100`,
+	}
+	for path, message := range expected {
+		found := false
+		for _, p := range problems {
+			if p.Path == path && p.Severity == "error" && strings.Contains(p.Message, message) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected an error at %s containing %q, got %+v", path, message, problems)
+		}
+	}
+}
+
+func TestValidateDefinitionsInvalidFile(t *testing.T) {
+	problems := validateTestDefinitions(t, map[string]string{"broken.json": `{"id":`})
+	if len(problems) != 1 || problems[0].File == "" || problems[0].Severity != "error" {
+		t.Fatalf("expected a single error for the file, got %+v", problems)
+	}
+}
+
+func validateTestDefinitions(t *testing.T, files map[string]string) []DefinitionProblem {
+	dir := t.TempDir()
+	var paths []string
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	problems, err := ValidateDefinitions(paths, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return problems
+}
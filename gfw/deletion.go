@@ -0,0 +1,45 @@
+package gfw
+
+import (
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// deletionProtectionSchema is kept in the state only, so the protection does
+// not depend on the server and has to be disabled with an apply before the
+// resource can be destroyed.
+func deletionProtectionSchema(enabled bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  enabled,
+	}
+}
+
+// checkDeletionProtection refuses to delete a protected resource.
+func checkDeletionProtection(d *schema.ResourceData, kind string) diag.Diagnostics {
+	return deletionProtectionDiagnostics(d.Get("deletion_protection").(bool), kind, d.Id())
+}
+
+func deletionProtectionDiagnostics(protected bool, kind string, id string) diag.Diagnostics {
+	if !protected {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Resource is protected against deletion",
+		Detail:   "Set deletion_protection to false and apply before destroying " + kind + " " + id + ".",
+	}}
+}
+
+// deletionProtectionAttribute is deletionProtectionSchema for the resources
+// of the framework provider.
+func deletionProtectionAttribute(enabled bool) resourceschema.BoolAttribute {
+	return resourceschema.BoolAttribute{
+		Optional: true,
+		Computed: true,
+		Default:  booldefault.StaticBool(enabled),
+	}
+}
@@ -0,0 +1,66 @@
+package gfw
+
+import (
+	"fmt"
+	"log"
+
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// diagsToPlanError turns the errors of a check run from CustomizeDiff into a
+// plan error. Warnings can not be surfaced from CustomizeDiff, so they are
+// logged and have to be reported again from Create, Read or Update.
+func diagsToPlanError(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == diag.Warning {
+			log.Printf("[WARN] %s: %s", d.Summary, d.Detail)
+		}
+	}
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			if d.Detail == "" {
+				return fmt.Errorf("%s", d.Summary)
+			}
+			return fmt.Errorf("%s: %s", d.Summary, d.Detail)
+		}
+	}
+	return nil
+}
+
+// diagsToWarnings downgrades the result of a check so it can be returned
+// without failing the operation.
+func diagsToWarnings(diags diag.Diagnostics) diag.Diagnostics {
+	warnings := make(diag.Diagnostics, len(diags))
+	for i, d := range diags {
+		d.Severity = diag.Warning
+		warnings[i] = d
+	}
+	return warnings
+}
+
+// diagsWarnings keeps the warnings of a check run from Read, whose errors are
+// reported by CustomizeDiff.
+func diagsWarnings(diags diag.Diagnostics) diag.Diagnostics {
+	var warnings diag.Diagnostics
+	for _, d := range diags {
+		if d.Severity == diag.Warning {
+			warnings = append(warnings, d)
+		}
+	}
+	return warnings
+}
+
+// frameworkDiagnostics converts the result of a check to the diagnostics of
+// the framework provider.
+func frameworkDiagnostics(diags diag.Diagnostics) fwdiag.Diagnostics {
+	var converted fwdiag.Diagnostics
+	for _, d := range diags {
+		if d.Severity == diag.Warning {
+			converted.AddWarning(d.Summary, d.Detail)
+		} else {
+			converted.AddError(d.Summary, d.Detail)
+		}
+	}
+	return converted
+}
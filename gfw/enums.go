@@ -0,0 +1,187 @@
+package gfw
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+const (
+	ENUM_DATASET_TYPES         = "dataset_types"
+	ENUM_DATASET_CATEGORIES    = "dataset_categories"
+	ENUM_DATASET_SUBCATEGORIES = "dataset_subcategories"
+	ENUM_DATASET_UNITS         = "dataset_units"
+	ENUM_DATAVIEW_CATEGORIES   = "dataview_categories"
+	ENUM_DATAVIEW_APPS         = "dataview_apps"
+	ENUM_DATAVIEW_CONFIG_TYPES = "dataview_config_types"
+	ENUM_WORKSPACE_CATEGORIES  = "workspace_categories"
+)
+
+// Enums holds the allowed values of the attributes validated at plan time,
+// keyed by enum name.
+type Enums map[string][]string
+
+// defaultEnums returns a copy of the enumerations built into the provider.
+func defaultEnums() Enums {
+	enums := Enums{}
+	enums.merge(Enums{
+		ENUM_DATASET_TYPES:         DATASET_TYPES,
+		ENUM_DATASET_CATEGORIES:    DATASET_CATEGORIES,
+		ENUM_DATASET_SUBCATEGORIES: DATASET_SUBCATEGORIES,
+		ENUM_DATASET_UNITS:         DATASET_UNITS,
+		ENUM_DATAVIEW_CATEGORIES:   DATAVIEW_TYPES,
+		ENUM_DATAVIEW_APPS:         DATAVIEW_APPS,
+		ENUM_DATAVIEW_CONFIG_TYPES: DATAVIEW_CONFIG_TYPES,
+		ENUM_WORKSPACE_CATEGORIES:  WORKSPACE_CATEGORIES,
+	})
+	return enums
+}
+
+// merge adds the values of other to the enums.
+func (e Enums) merge(other Enums) {
+	for name, values := range other {
+		for _, v := range values {
+			if v != "" && !utils.ContainsString(e[name], v) {
+				e[name] = append(e[name], v)
+			}
+		}
+		sort.Strings(e[name])
+	}
+}
+
+// override replaces the enums present in other.
+func (e Enums) override(other Enums) {
+	for name, values := range other {
+		e[name] = values
+	}
+}
+
+// loadEnumsFile reads a JSON file mapping enum names to their allowed values.
+// The enums present in the file replace the built-in ones.
+func loadEnumsFile(path string) (Enums, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	enums := Enums{}
+	if err := json.Unmarshal(content, &enums); err != nil {
+		return nil, fmt.Errorf("invalid enums file %q: %v", path, err)
+	}
+	known := defaultEnums()
+	for name := range enums {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("invalid enums file %q: unknown enum %q", path, name)
+		}
+	}
+	return enums, nil
+}
+
+// fetchEnums collects the values in use by the datasets, dataviews and
+// workspaces of the API, so values added on the backend are accepted without
+// a provider release. The API has no enum endpoint, valid values not in use
+// yet are missing from the result.
+func fetchEnums(c *api.GFWClient) (Enums, error) {
+	enums := Enums{}
+	datasets, err := c.GetAllDatasets()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range datasets {
+		enums.merge(Enums{
+			ENUM_DATASET_TYPES:         {d.Type},
+			ENUM_DATASET_CATEGORIES:    {d.Category},
+			ENUM_DATASET_SUBCATEGORIES: {d.Subcategory},
+			ENUM_DATASET_UNITS:         {d.Unit},
+		})
+	}
+
+	dataviews, err := c.GetAllDataviews()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dataviews {
+		enums.merge(Enums{
+			ENUM_DATAVIEW_CATEGORIES: {d.Category},
+			ENUM_DATAVIEW_APPS:       {d.App},
+		})
+		if d.Config != nil {
+			enums.merge(Enums{ENUM_DATAVIEW_CONFIG_TYPES: {d.Config.Type}})
+		}
+	}
+
+	workspaces, err := c.GetAllWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workspaces {
+		enums.merge(Enums{ENUM_WORKSPACE_CATEGORIES: {w.Category}})
+	}
+
+	return enums, nil
+}
+
+// enumValue is an attribute value that has to belong to one of the enums.
+type enumValue struct {
+	Path  string
+	Enum  string
+	Value string
+}
+
+// checkEnumValues reports every value not present in its enum as an error.
+// Callers decide whether unknown values are errors or warnings.
+func checkEnumValues(enums Enums, values []enumValue) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, v := range values {
+		if v.Value == "" || utils.ContainsString(enums[v.Enum], v.Value) {
+			continue
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Unknown value for %s", v.Path),
+			Detail:   fmt.Sprintf("expected %s to be one of %v, got %q", v.Path, enums[v.Enum], v.Value),
+		})
+	}
+	return diags
+}
+
+// splitEnumValues separates the values checked strictly from the ones only
+// warned about: every value when strict_enums is disabled, and the values of
+// enums inferred from the API, which can not list every valid value.
+func splitEnumValues(config *Config, values []enumValue) ([]enumValue, []enumValue) {
+	var strict, lax []enumValue
+	for _, v := range values {
+		if config.StrictEnums && !config.InferredEnums[v.Enum] {
+			strict = append(strict, v)
+		} else {
+			lax = append(lax, v)
+		}
+	}
+	return strict, lax
+}
+
+// validateEnumValues is called from CustomizeDiff. Unknown values fail the
+// plan when they are checked strictly and are logged otherwise.
+func validateEnumValues(config *Config, values []enumValue) error {
+	strict, lax := splitEnumValues(config, values)
+	diags := checkEnumValues(config.Enums, strict)
+	diags = append(diags, diagsToWarnings(checkEnumValues(config.Enums, lax))...)
+	return diagsToPlanError(diags)
+}
+
+// enumWarnings returns the unknown values not checked strictly as warnings,
+// so they are reported once the resource is applied.
+func enumWarnings(config *Config, values []enumValue) diag.Diagnostics {
+	_, lax := splitEnumValues(config, values)
+	return diagsToWarnings(checkEnumValues(config.Enums, lax))
+}
+
+// resourceGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type resourceGetter interface {
+	Get(key string) interface{}
+}
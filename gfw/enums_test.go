@@ -0,0 +1,80 @@
+package gfw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Every page of the API is read, and values missing from the inferred enums
+// are warnings even with strict_enums.
+func TestFetchEnums(t *testing.T) {
+	s := fake.NewServer()
+	s.PageSize = 1
+	t.Cleanup(s.Close)
+	for _, d := range []map[string]interface{}{
+		{"id": "first:v1", "type": "4wings:v1"},
+		{"id": "second:v1", "type": "new-type:v1"},
+	} {
+		if _, err := s.Put(fake.Datasets, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	enums, err := fetchEnums(s.APIClient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utils.ContainsString(enums[ENUM_DATASET_TYPES], "new-type:v1") {
+		t.Fatalf("expected the type of the second page to be fetched, got %v", enums[ENUM_DATASET_TYPES])
+	}
+
+	values := []enumValue{{Path: "type", Enum: ENUM_DATASET_TYPES, Value: "unused-type:v1"}}
+	config := &Config{Enums: defaultEnums(), StrictEnums: true}
+	if err := validateEnumValues(config, values); err == nil {
+		t.Error("expected an error for an unknown built-in enum value")
+	}
+	config.InferredEnums = map[string]bool{ENUM_DATASET_TYPES: true}
+	if err := validateEnumValues(config, values); err != nil {
+		t.Errorf("expected no error for an unknown inferred enum value, got %v", err)
+	}
+	if diags := enumWarnings(config, values); len(diags) != 1 || diags.HasError() {
+		t.Errorf("expected a warning for an unknown inferred enum value, got %v", diags)
+	}
+}
+
+// With strict_enums and fetch_enums, only the enums with values fetched from
+// the API are inferred, the others are still checked strictly.
+func TestProviderConfigureInfersFetchedEnums(t *testing.T) {
+	s := fake.NewServer()
+	t.Cleanup(s.Close)
+	if _, err := s.Put(fake.Datasets, map[string]interface{}{"id": "first:v1", "type": "new-type:v1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"url":          s.URL,
+		"token":        fake.Token,
+		"strict_enums": true,
+		"fetch_enums":  true,
+	})
+	m, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	config := m.(*Config)
+	if !config.InferredEnums[ENUM_DATASET_TYPES] {
+		t.Errorf("expected %s to be inferred, got %v", ENUM_DATASET_TYPES, config.InferredEnums)
+	}
+	if config.InferredEnums[ENUM_DATAVIEW_CATEGORIES] {
+		t.Errorf("expected %s not to be inferred, nothing was fetched for it", ENUM_DATAVIEW_CATEGORIES)
+	}
+
+	values := []enumValue{{Path: "category", Enum: ENUM_DATAVIEW_CATEGORIES, Value: "unknown-category"}}
+	if err := validateEnumValues(config, values); err == nil {
+		t.Error("expected an error for an unknown value of an enum not fetched")
+	}
+}
@@ -0,0 +1,476 @@
+package gfw
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ExportOptions selects the objects exported, an empty field selects all.
+type ExportOptions struct {
+	// Resources are resource types, such as gfw_dataset.
+	Resources []string
+	// Types are dataset types or dataview config types, such as 4wings:v1.
+	// Objects without a type are left out when set.
+	Types []string
+	// Categories are the categories of datasets, dataviews and workspaces.
+	// Objects without a category are left out when set.
+	Categories []string
+	// Prefix is a prefix of the ID or the name of the objects.
+	Prefix string
+}
+
+// exportObject is an object listed by the API.
+type exportObject struct {
+	ID       string
+	Label    string
+	Type     string
+	Category string
+}
+
+type exporter struct {
+	resourceType string
+	resource     func() *schema.Resource
+	list         func(c *api.GFWClient) ([]exportObject, error)
+	// frameworkResource replaces resource for the resources served by the
+	// framework provider.
+	frameworkResource func() resource.Resource
+}
+
+// Resources exported in dependency order, so the generated configuration reads
+// top to bottom.
+var EXPORTERS = []exporter{
+	{"gfw_action", resourceAction, listActions, nil},
+	{"gfw_resource", resourceResource, listResources, nil},
+	{"gfw_permission", resourcePermission, listPermissions, nil},
+	{"gfw_role", resourceRole, listRoles, nil},
+	{"gfw_user_group", resourceUserGroup, listUserGroups, nil},
+	{"gfw_dataset", resourceDataset, listDatasets, nil},
+	{"gfw_dataview", resourceDataview, listDataviews, nil},
+	{"gfw_workspace", nil, listWorkspaces, newWorkspaceResource},
+}
+
+var nonIdentifierCharacters = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// Export writes a resource block and an import block for every object of the
+// API selected by options. The attributes are those the resources read, so a
+// plan after importing shows no changes. Sensitive and write-only attributes,
+// such as workspace passwords, cannot be read and are left out.
+func Export(ctx context.Context, c *api.GFWClient, w io.Writer, options ExportOptions) error {
+	for _, resourceType := range options.Resources {
+		if !exportable(resourceType) {
+			return fmt.Errorf("resource type %s can not be exported", resourceType)
+		}
+	}
+	meta := &Config{Client: c, Enums: defaultEnums(), ViewportMaxZoom: 22}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	labels := map[string]bool{}
+	for _, e := range EXPORTERS {
+		if len(options.Resources) > 0 && !utils.ContainsString(options.Resources, e.resourceType) {
+			continue
+		}
+		objects, err := e.list(c)
+		if err != nil {
+			return fmt.Errorf("unable to list %s: %v", e.resourceType, err)
+		}
+		for _, object := range objects {
+			if !options.selects(object) {
+				continue
+			}
+			var write func(body *hclwrite.Body)
+			if e.frameworkResource != nil {
+				s, values, err := readExportedFrameworkObject(ctx, e.frameworkResource(), object.ID, meta)
+				if err != nil {
+					return fmt.Errorf("unable to read %s %s: %v", e.resourceType, object.ID, err)
+				}
+				if values == nil {
+					continue
+				}
+				write = func(body *hclwrite.Body) {
+					writeExportedFrameworkAttributes(body, s.Attributes, s.Blocks, values)
+				}
+			} else {
+				d, err := readExportedObject(ctx, e.resource(), object.ID, meta)
+				if err != nil {
+					return fmt.Errorf("unable to read %s %s: %v", e.resourceType, object.ID, err)
+				}
+				if d == nil {
+					continue
+				}
+				write = func(body *hclwrite.Body) {
+					writeExportedAttributes(body, e.resource().Schema, func(key string) interface{} {
+						return d.Get(key)
+					})
+				}
+			}
+			label := exportLabel(object.Label, labels)
+
+			importBlock := body.AppendNewBlock("import", nil).Body()
+			importBlock.SetAttributeTraversal("to", hcl.Traversal{
+				hcl.TraverseRoot{Name: e.resourceType},
+				hcl.TraverseAttr{Name: label},
+			})
+			importBlock.SetAttributeValue("id", cty.StringVal(object.ID))
+			body.AppendNewline()
+
+			resourceBlock := body.AppendNewBlock("resource", []string{e.resourceType, label}).Body()
+			write(resourceBlock)
+			body.AppendNewline()
+		}
+	}
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+func exportable(resourceType string) bool {
+	for _, e := range EXPORTERS {
+		if e.resourceType == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+func (options ExportOptions) selects(object exportObject) bool {
+	if len(options.Types) > 0 && !utils.ContainsString(options.Types, object.Type) {
+		return false
+	}
+	if len(options.Categories) > 0 && !utils.ContainsString(options.Categories, object.Category) {
+		return false
+	}
+	return strings.HasPrefix(object.ID, options.Prefix) || strings.HasPrefix(object.Label, options.Prefix)
+}
+
+// readExportedObject imports and reads an object as terraform import does,
+// it returns nil when the object is gone.
+func readExportedObject(ctx context.Context, resource *schema.Resource, id string, meta *Config) (*schema.ResourceData, error) {
+	d := resource.Data(nil)
+	d.SetId(id)
+	if resource.Importer != nil && resource.Importer.StateContext != nil {
+		imported, err := resource.Importer.StateContext(ctx, d, meta)
+		if err != nil {
+			return nil, err
+		}
+		d = imported[0]
+	}
+	for _, diagnostic := range resource.ReadContext(ctx, d, meta) {
+		if diagnostic.Severity == diag.Error {
+			return nil, fmt.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+	}
+	if d.Id() == "" {
+		return nil, nil
+	}
+	return d, nil
+}
+
+// readExportedFrameworkObject is readExportedObject for the resources of the
+// framework provider, it returns the values of the state as ResourceData.Get
+// does.
+func readExportedFrameworkObject(ctx context.Context, r resource.Resource, id string, meta *Config) (resourceschema.Schema, map[string]interface{}, error) {
+	if configurable, ok := r.(resource.ResourceWithConfigure); ok {
+		configurable.Configure(ctx, resource.ConfigureRequest{ProviderData: meta}, &resource.ConfigureResponse{})
+	}
+	schemaResp := resource.SchemaResponse{}
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	s := schemaResp.Schema
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	var diags fwdiag.Diagnostics
+	if importable, ok := r.(resource.ResourceWithImportState); ok {
+		importResp := resource.ImportStateResponse{State: state}
+		importable.ImportState(ctx, resource.ImportStateRequest{ID: id}, &importResp)
+		diags.Append(importResp.Diagnostics...)
+		state = importResp.State
+	} else {
+		diags.Append(state.SetAttribute(ctx, path.Root("id"), id)...)
+	}
+	readResp := resource.ReadResponse{State: state}
+	if !diags.HasError() {
+		r.Read(ctx, resource.ReadRequest{State: state}, &readResp)
+		diags.Append(readResp.Diagnostics...)
+	}
+	for _, diagnostic := range diags.Errors() {
+		return s, nil, fmt.Errorf("%s: %s", diagnostic.Summary(), diagnostic.Detail())
+	}
+	if readResp.State.Raw.IsNull() {
+		return s, nil, nil
+	}
+	var object types.Object
+	if diags := readResp.State.Get(ctx, &object); diags.HasError() {
+		return s, nil, fmt.Errorf("%s: %s", diags.Errors()[0].Summary(), diags.Errors()[0].Detail())
+	}
+	return s, sdkValue(object).(map[string]interface{}), nil
+}
+
+// exportLabel returns a unique resource name made of the characters allowed
+// in identifiers.
+func exportLabel(name string, labels map[string]bool) string {
+	label := strings.Trim(nonIdentifierCharacters.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') {
+		label = "gfw_" + label
+	}
+	unique := label
+	for i := 2; labels[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	labels[unique] = true
+	return unique
+}
+
+// writeExportedAttributes writes the arguments of a schema, leaving out the
+// computed ones and those holding their default or zero value.
+func writeExportedAttributes(body *hclwrite.Body, s map[string]*schema.Schema, get func(key string) interface{}) {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		attribute := s[key]
+		if (!attribute.Required && !attribute.Optional) || attribute.Sensitive {
+			continue
+		}
+		value := get(key)
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		if !attribute.Required && isDefaultValue(attribute, value) {
+			continue
+		}
+		if block, ok := attribute.Elem.(*schema.Resource); ok {
+			for _, element := range value.([]interface{}) {
+				fields, _ := element.(map[string]interface{})
+				writeExportedAttributes(body.AppendNewBlock(key, nil).Body(), block.Schema, func(key string) interface{} {
+					return fields[key]
+				})
+			}
+			continue
+		}
+		body.SetAttributeValue(key, exportValue(value))
+	}
+}
+
+// writeExportedFrameworkAttributes is writeExportedAttributes for the schemas
+// of the framework provider, whose defaults are all zero values. Blocks other
+// than lists, such as timeouts, are left out.
+func writeExportedFrameworkAttributes(body *hclwrite.Body, attributes map[string]resourceschema.Attribute, blocks map[string]resourceschema.Block, values map[string]interface{}) {
+	keys := make([]string, 0, len(attributes)+len(blocks))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	for key := range blocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values[key]
+		if block, ok := blocks[key].(resourceschema.ListNestedBlock); ok {
+			elements, _ := value.([]interface{})
+			for _, element := range elements {
+				fields, _ := element.(map[string]interface{})
+				writeExportedFrameworkAttributes(body.AppendNewBlock(key, nil).Body(), block.NestedObject.Attributes, block.NestedObject.Blocks, fields)
+			}
+			continue
+		}
+		attribute, ok := attributes[key]
+		if !ok || (!attribute.IsRequired() && !attribute.IsOptional()) || attribute.IsSensitive() || attribute.IsWriteOnly() {
+			continue
+		}
+		if !attribute.IsRequired() && isDefaultValue(&schema.Schema{}, value) {
+			continue
+		}
+		body.SetAttributeValue(key, exportValue(value))
+	}
+}
+
+func isDefaultValue(attribute *schema.Schema, value interface{}) bool {
+	if attribute.Default != nil {
+		// Defaults of floats are often written as integers.
+		return fmt.Sprint(attribute.Default) == fmt.Sprint(value)
+	}
+	if value == nil {
+		return true
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return reflect.ValueOf(value).IsZero()
+}
+
+func exportValue(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return cty.EmptyTupleVal
+		}
+		values := make([]cty.Value, len(v))
+		for i, e := range v {
+			values[i] = exportValue(e)
+		}
+		return cty.TupleVal(values)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal
+		}
+		values := make(map[string]cty.Value, len(v))
+		for k, e := range v {
+			values[k] = exportValue(e)
+		}
+		return cty.ObjectVal(values)
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
+
+func listActions(c *api.GFWClient) ([]exportObject, error) {
+	actions, err := c.GetActions()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, action := range *actions {
+		objects = append(objects, exportObject{ID: strconv.Itoa(action.ID), Label: action.Name})
+	}
+	return objects, nil
+}
+
+func listResources(c *api.GFWClient) ([]exportObject, error) {
+	resources, err := c.GetResources()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, resource := range *resources {
+		objects = append(objects, exportObject{
+			ID:    strconv.Itoa(resource.ID),
+			Label: fmt.Sprintf("%s_%s", resource.Type, resource.Value),
+		})
+	}
+	return objects, nil
+}
+
+func listPermissions(c *api.GFWClient) ([]exportObject, error) {
+	permissions, err := c.GetPermissions()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, permission := range *permissions {
+		objects = append(objects, exportObject{ID: strconv.Itoa(permission.ID), Label: permission.Name})
+	}
+	return objects, nil
+}
+
+func listRoles(c *api.GFWClient) ([]exportObject, error) {
+	roles, err := c.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, role := range *roles {
+		objects = append(objects, exportObject{ID: strconv.Itoa(role.ID), Label: role.Name})
+	}
+	return objects, nil
+}
+
+func listUserGroups(c *api.GFWClient) ([]exportObject, error) {
+	userGroups, err := c.GetUserGroups()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, userGroup := range *userGroups {
+		objects = append(objects, exportObject{ID: strconv.Itoa(userGroup.ID), Label: userGroup.Name})
+	}
+	return objects, nil
+}
+
+func listDatasets(c *api.GFWClient) ([]exportObject, error) {
+	datasets, err := c.GetAllDatasets()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, dataset := range datasets {
+		objects = append(objects, exportObject{
+			ID:       dataset.ID,
+			Label:    dataset.ID,
+			Type:     dataset.Type,
+			Category: dataset.Category,
+		})
+	}
+	return objects, nil
+}
+
+func listDataviews(c *api.GFWClient) ([]exportObject, error) {
+	dataviews, err := c.GetAllDataviews()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, dataview := range dataviews {
+		object := exportObject{
+			ID:       strconv.Itoa(dataview.ID),
+			Label:    dataview.Slug,
+			Category: dataview.Category,
+		}
+		if object.Label == "" {
+			object.Label = dataview.Name
+		}
+		if dataview.Config != nil {
+			object.Type = dataview.Config.Type
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func listWorkspaces(c *api.GFWClient) ([]exportObject, error) {
+	workspaces, err := c.GetAllWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	var objects []exportObject
+	for _, workspace := range workspaces {
+		objects = append(objects, exportObject{
+			ID:       workspace.ID,
+			Label:    workspace.ID,
+			Category: workspace.Category,
+		})
+	}
+	return objects, nil
+}
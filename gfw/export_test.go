@@ -0,0 +1,128 @@
+package gfw
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func testExportServer(t *testing.T) *fake.Server {
+	s := testAccServer(t)
+	objects := []struct {
+		collection string
+		object     map[string]interface{}
+	}{
+		{fake.Datasets, map[string]interface{}{
+			"id": "public-mpa:v1", "name": "Protected areas", "description": "Marine protected areas", "type": "context-layer:v1", "category": "context-layer", "status": "done",
+			"configuration": map[string]interface{}{"contextLayerV1": map[string]interface{}{"idProperty": "id", "fields": []string{"name"}}},
+		}},
+		{fake.Datasets, map[string]interface{}{
+			"id": "public-sst:v1", "name": "Sea surface temperature", "description": "Daily sea surface temperature", "type": "4wings:v1", "category": "environment", "status": "done",
+			"configuration": map[string]interface{}{"fourwingsV1": map[string]interface{}{"intervals": []string{"DAY"}, "max": 30.5, "min": 0}},
+		}},
+		{fake.Dataviews, map[string]interface{}{
+			"slug": "sst", "name": "Sea surface temperature", "description": "Daily sea surface temperature", "category": "environment", "app": "fishing-map",
+			"config": map[string]interface{}{"type": "HEATMAP_ANIMATED", "color": "#00FFBC", "datasets": []string{"public-sst:v1"}},
+		}},
+		{fake.Workspaces, map[string]interface{}{
+			"id": "ocean-health", "name": "Ocean health", "description": "Environment layers", "category": "marine-manager", "app": "fishing-map",
+			"startAt": "2023-01-01T00:00:00.000Z", "endAt": "2024-01-01T00:00:00.000Z", "state": map[string]interface{}{"daysFromLatest": 30},
+			"viewport": map[string]interface{}{"zoom": 3, "latitude": 10, "longitude": -20},
+		}},
+		{fake.Roles, map[string]interface{}{"name": "Editors", "description": "Edit datasets"}},
+	}
+	for _, o := range objects {
+		if _, err := s.Put(o.collection, o.object); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestExport(t *testing.T) {
+	s := testExportServer(t)
+	var out bytes.Buffer
+	err := Export(context.Background(), s.APIClient(), &out, ExportOptions{Resources: []string{"gfw_dataset"}, Types: []string{"4wings:v1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hcl := out.String()
+	for _, expected := range []string{
+		"to = gfw_dataset.public_sst_v1",
+		`id = "public-sst:v1"`,
+		`resource "gfw_dataset" "public_sst_v1" {`,
+		"fourwings_v1 {",
+		`intervals = ["DAY"]`,
+		"max       = 30.5",
+	} {
+		if !strings.Contains(hcl, expected) {
+			t.Errorf("%q missing from\n%s", expected, hcl)
+		}
+	}
+	for _, unexpected := range []string{"public-mpa:v1", "gfw_role", "deletion_protection"} {
+		if strings.Contains(hcl, unexpected) {
+			t.Errorf("%q unexpected in\n%s", unexpected, hcl)
+		}
+	}
+
+	if err := Export(context.Background(), s.APIClient(), &out, ExportOptions{Resources: []string{"gfw_unknown"}}); err == nil {
+		t.Error("expected an error for an unknown resource type")
+	}
+}
+
+// Workspaces are served by the framework provider and read through it.
+func TestExportWorkspace(t *testing.T) {
+	s := testExportServer(t)
+	var out bytes.Buffer
+	if err := Export(context.Background(), s.APIClient(), &out, ExportOptions{Resources: []string{"gfw_workspace"}}); err != nil {
+		t.Fatal(err)
+	}
+	hcl := out.String()
+	for _, expected := range []string{
+		"to = gfw_workspace.ocean_health",
+		`resource "gfw_workspace" "ocean_health" {`,
+		`category    = "marine-manager"`,
+		`state       = "{\"daysFromLatest\":30}"`,
+		"viewport {",
+		"longitude = -20",
+	} {
+		if !strings.Contains(hcl, expected) {
+			t.Errorf("%q missing from\n%s", expected, hcl)
+		}
+	}
+	for _, unexpected := range []string{"deletion_protection", "timeouts", "aoi", "password"} {
+		if strings.Contains(hcl, unexpected) {
+			t.Errorf("%q unexpected in\n%s", unexpected, hcl)
+		}
+	}
+}
+
+// The exported configuration must import without changes.
+func TestAccExport_basic(t *testing.T) {
+	s := testExportServer(t)
+	var out bytes.Buffer
+	if err := Export(context.Background(), s.APIClient(), &out, ExportOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: out.String(),
+			},
+			{
+				Config:   out.String(),
+				PlanOnly: true,
+			},
+			{
+				// Allow the datasets to be destroyed at the end of the test.
+				Config: regexp.MustCompile(`(resource "gfw_dataset" .* \{)`).ReplaceAllString(out.String(), "$1\n  deletion_protection = false"),
+			},
+		},
+	})
+}
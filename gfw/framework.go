@@ -0,0 +1,182 @@
+package gfw
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Helpers of the resources moved to the framework provider. SDKv2 states
+// store unset optional attributes as empty values while the framework keeps
+// them null, values are read back as null unless the prior value was empty.
+
+// sdkValue returns a value as ResourceData.Get does, null and unknown values
+// being the zero value of their type, so the helpers shared with the SDKv2
+// resources can be reused.
+func sdkValue(value attr.Value) interface{} {
+	switch v := value.(type) {
+	case types.String:
+		return v.ValueString()
+	case types.Bool:
+		return v.ValueBool()
+	case types.Int64:
+		return int(v.ValueInt64())
+	case types.Float64:
+		return v.ValueFloat64()
+	case types.List:
+		return sdkValues(v.Elements())
+	case types.Set:
+		return sdkValues(v.Elements())
+	case types.Object:
+		m := map[string]interface{}{}
+		for key, e := range v.Attributes() {
+			m[key] = sdkValue(e)
+		}
+		return m
+	}
+	return nil
+}
+
+func sdkValues(values []attr.Value) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, e := range values {
+		list[i] = sdkValue(e)
+	}
+	return list
+}
+
+// listObjects returns the elements of a list of blocks, none when the list is
+// null or unknown.
+func listObjects(list types.List) []types.Object {
+	var objects []types.Object
+	for _, e := range list.Elements() {
+		if object, ok := e.(types.Object); ok {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// knownList reports whether the list and all its values are known.
+func knownList(list types.List) bool {
+	if list.IsUnknown() {
+		return false
+	}
+	for _, e := range list.Elements() {
+		if e.IsUnknown() {
+			return false
+		}
+		if object, ok := e.(types.Object); ok {
+			for _, a := range object.Attributes() {
+				if a.IsUnknown() {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+func optionalString(value string, prior types.String) types.String {
+	if value == "" && (prior.IsNull() || prior.IsUnknown() || prior.ValueString() != "") {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
+// optionalJSON is optionalString for JSON documents, the prior value is kept
+// when it encodes the same document: the framework rejects applied values
+// differing from the configured ones.
+func optionalJSON(value string, prior types.String) types.String {
+	if value != "" && !prior.IsNull() && !prior.IsUnknown() && equivalentJSON(value, prior.ValueString()) {
+		return prior
+	}
+	return optionalString(value, prior)
+}
+
+func equivalentJSON(a, b string) bool {
+	var va, vb interface{}
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func optionalStringList(ctx context.Context, values []string, prior types.List) (types.List, fwdiag.Diagnostics) {
+	if len(values) == 0 && (prior.IsNull() || prior.IsUnknown() || len(prior.Elements()) > 0) {
+		return types.ListNull(types.StringType), nil
+	}
+	if values == nil {
+		values = []string{}
+	}
+	// JSON elements are kept as written, see optionalJSON.
+	priorValues := prior.Elements()
+	if len(priorValues) == len(values) {
+		values = append([]string{}, values...)
+		for i, p := range priorValues {
+			if p, ok := p.(types.String); ok && !p.IsNull() && !p.IsUnknown() && equivalentJSON(values[i], p.ValueString()) {
+				values[i] = p.ValueString()
+			}
+		}
+	}
+	return types.ListValueFrom(ctx, types.StringType, values)
+}
+
+func optionalInt64Set(ctx context.Context, values []int, prior types.Set) (types.Set, fwdiag.Diagnostics) {
+	if len(values) == 0 && (prior.IsNull() || prior.IsUnknown() || len(prior.Elements()) > 0) {
+		return types.SetNull(types.Int64Type), nil
+	}
+	elements := make([]int64, len(values))
+	for i, v := range values {
+		elements[i] = int64(v)
+	}
+	return types.SetValueFrom(ctx, types.Int64Type, elements)
+}
+
+// priorString returns an attribute of a prior block, null when there is none.
+func priorString(prior map[string]attr.Value, key string) types.String {
+	if v, ok := prior[key].(types.String); ok {
+		return v
+	}
+	return types.StringNull()
+}
+
+func priorList(prior map[string]attr.Value, key string, elementType attr.Type) types.List {
+	if v, ok := prior[key].(types.List); ok {
+		return v
+	}
+	return types.ListNull(elementType)
+}
+
+// sdkStringValidator runs an SDKv2 validation function on a string attribute,
+// so both providers validate and report alike.
+type sdkStringValidator struct {
+	validate schema.SchemaValidateFunc
+}
+
+func (v sdkStringValidator) Description(ctx context.Context) string {
+	return "value must pass the SDKv2 validation of the attribute"
+}
+
+func (v sdkStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v sdkStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	warnings, errs := v.validate(req.ConfigValue.ValueString(), req.Path.String())
+	for _, warning := range warnings {
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Invalid attribute value", warning)
+	}
+	for _, err := range errs {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid attribute value", err.Error())
+	}
+}
@@ -0,0 +1,229 @@
+package gfw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// datasetIDFunction builds a versioned dataset ID, such as
+// public-global-fishing-effort:v3.0.
+type datasetIDFunction struct{}
+
+func newDatasetIDFunction() function.Function {
+	return datasetIDFunction{}
+}
+
+func (f datasetIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dataset_id"
+}
+
+func (f datasetIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build a dataset ID",
+		Description: "Returns the ID of a version of a dataset, the name and the version joined by :v. The version may start with v.",
+		Parameters: []function.Parameter{
+			function.StringParameter{Name: "name", Description: "Name of the dataset, such as public-global-fishing-effort."},
+			function.StringParameter{Name: "version", Description: "Version of the dataset, such as 3.0."},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f datasetIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name, version string
+	resp.Error = req.Arguments.Get(ctx, &name, &version)
+	if resp.Error != nil {
+		return
+	}
+	id, err := datasetID(name, version)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, id)
+}
+
+func datasetID(name, version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if name == "" || strings.Contains(name, ":") {
+		return "", fmt.Errorf("invalid dataset name %q", name)
+	}
+	if version == "" || strings.Contains(version, ":") {
+		return "", fmt.Errorf("invalid dataset version %q", version)
+	}
+	return fmt.Sprintf("%s:v%s", name, version), nil
+}
+
+// parseDatasetIDFunction splits a dataset ID into its name and version, the
+// inverse of dataset_id.
+type parseDatasetIDFunction struct{}
+
+var DATASET_ID_ATTRIBUTE_TYPES = map[string]attr.Type{
+	"name":    types.StringType,
+	"version": types.StringType,
+}
+
+func newParseDatasetIDFunction() function.Function {
+	return parseDatasetIDFunction{}
+}
+
+func (f parseDatasetIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_dataset_id"
+}
+
+func (f parseDatasetIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Parse a dataset ID",
+		Description: "Returns the name and the version, without its v prefix, of a dataset ID such as public-global-fishing-effort:v3.0.",
+		Parameters: []function.Parameter{
+			function.StringParameter{Name: "id", Description: "ID of the dataset."},
+		},
+		Return: function.ObjectReturn{AttributeTypes: DATASET_ID_ATTRIBUTE_TYPES},
+	}
+}
+
+func (f parseDatasetIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var id string
+	resp.Error = req.Arguments.Get(ctx, &id)
+	if resp.Error != nil {
+		return
+	}
+	name, version, err := parseDatasetID(id)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	result, diags := types.ObjectValue(DATASET_ID_ATTRIBUTE_TYPES, map[string]attr.Value{
+		"name":    types.StringValue(name),
+		"version": types.StringValue(version),
+	})
+	if diags.HasError() {
+		resp.Error = function.FuncErrorFromDiags(ctx, diags)
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+func parseDatasetID(id string) (string, string, error) {
+	i := strings.LastIndex(id, ":v")
+	if i <= 0 || i+2 == len(id) {
+		return "", "", fmt.Errorf("dataset ID %q is not of the form name:vversion", id)
+	}
+	return id[:i], id[i+2:], nil
+}
+
+// workspaceIDFunction returns the ID the API gives to a workspace created
+// without one.
+type workspaceIDFunction struct{}
+
+func newWorkspaceIDFunction() function.Function {
+	return workspaceIDFunction{}
+}
+
+func (f workspaceIDFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "workspace_id"
+}
+
+func (f workspaceIDFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build a workspace ID",
+		Description: "Returns the ID of a workspace created without workspace_id: the snake case name, with a -public suffix for public workspaces.",
+		Parameters: []function.Parameter{
+			function.StringParameter{Name: "name", Description: "Name of the workspace."},
+			function.BoolParameter{Name: "public", Description: "Whether the workspace is public."},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f workspaceIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name string
+	var public bool
+	resp.Error = req.Arguments.Get(ctx, &name, &public)
+	if resp.Error != nil {
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, api.WorkspaceID(name, public))
+}
+
+// dataviewInstanceConfigFunction encodes an element of the datasets_config
+// of dataviews and dataview instances.
+type dataviewInstanceConfigFunction struct{}
+
+func newDataviewInstanceConfigFunction() function.Function {
+	return dataviewInstanceConfigFunction{}
+}
+
+func (f dataviewInstanceConfigFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dataview_instance_config"
+}
+
+func (f dataviewInstanceConfigFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encode a datasets_config element",
+		Description: "Returns the JSON of a datasets_config element, as the API reads it back, so plans show no changes. " +
+			"The params and query maps become lists of id and value objects.",
+		Parameters: []function.Parameter{
+			function.StringParameter{Name: "dataset_id", Description: "ID of the dataset."},
+			function.StringParameter{Name: "endpoint", Description: "Endpoint of the dataset, such as 4wings-tiles."},
+			function.MapParameter{Name: "params", ElementType: types.StringType, AllowNullValue: true, Description: "Path parameters of the endpoint."},
+			function.MapParameter{Name: "query", ElementType: types.StringType, AllowNullValue: true, Description: "Query parameters of the endpoint."},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f dataviewInstanceConfigFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var datasetID, endpoint string
+	var params, query types.Map
+	resp.Error = req.Arguments.Get(ctx, &datasetID, &endpoint, &params, &query)
+	if resp.Error != nil {
+		return
+	}
+	config := map[string]interface{}{
+		"datasetId": datasetID,
+		"endpoint":  endpoint,
+	}
+	for key, value := range map[string]types.Map{"params": params, "query": query} {
+		var values map[string]string
+		if !value.IsNull() {
+			if diags := value.ElementsAs(ctx, &values, false); diags.HasError() {
+				resp.Error = function.FuncErrorFromDiags(ctx, diags)
+				return
+			}
+		}
+		if key == "query" && len(values) == 0 {
+			continue
+		}
+		config[key] = datasetsConfigParams(values)
+	}
+	body, err := json.Marshal(config)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, string(body))
+}
+
+// datasetsConfigParams turns a map into the id and value list the API uses
+// for params and query, sorted by id.
+func datasetsConfigParams(values map[string]string) []map[string]interface{} {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		params[i] = map[string]interface{}{"id": key, "value": values[key]}
+	}
+	return params
+}
@@ -0,0 +1,170 @@
+package gfw
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDatasetIDFunctions(t *testing.T) {
+	for _, version := range []string{"3.0", "v3.0"} {
+		got := runTestFunction(t, newDatasetIDFunction(), types.StringValue("public-global-fishing-effort"), types.StringValue(version))
+		if got != types.StringValue("public-global-fishing-effort:v3.0") {
+			t.Errorf("dataset_id with version %s returned %s", version, got)
+		}
+	}
+
+	got := runTestFunction(t, newParseDatasetIDFunction(), types.StringValue("public-global-fishing-effort:v3.0"))
+	expected := types.ObjectValueMust(DATASET_ID_ATTRIBUTE_TYPES, map[string]attr.Value{
+		"name":    types.StringValue("public-global-fishing-effort"),
+		"version": types.StringValue("3.0"),
+	})
+	if !got.Equal(expected) {
+		t.Errorf("parse_dataset_id returned %s", got)
+	}
+
+	for _, id := range []string{"public-global-fishing-effort", "public-global-fishing-effort:v", ":v1"} {
+		resp := callTestFunction(newParseDatasetIDFunction(), types.StringValue(id))
+		if resp.Error == nil {
+			t.Errorf("parse_dataset_id accepted %q", id)
+		}
+	}
+	if resp := callTestFunction(newDatasetIDFunction(), types.StringValue("mpa"), types.StringValue("")); resp.Error == nil {
+		t.Error("dataset_id accepted an empty version")
+	}
+}
+
+func TestWorkspaceIDFunction(t *testing.T) {
+	got := runTestFunction(t, newWorkspaceIDFunction(), types.StringValue("Fishing Effort"), types.BoolValue(true))
+	if got != types.StringValue("fishing_effort-public") {
+		t.Errorf("workspace_id returned %s", got)
+	}
+}
+
+func TestDataviewInstanceConfigFunction(t *testing.T) {
+	got := runTestFunction(t, newDataviewInstanceConfigFunction(),
+		types.StringValue("public-global-fishing-effort:v3.0"),
+		types.StringValue("4wings-tiles"),
+		types.MapNull(types.StringType),
+		types.MapValueMust(types.StringType, map[string]attr.Value{
+			"temporal-aggregation": types.StringValue("false"),
+			"interval":             types.StringValue("DAY"),
+		}),
+	)
+	expected := `{"datasetId":"public-global-fishing-effort:v3.0","endpoint":"4wings-tiles","params":[],"query":[{"id":"interval","value":"DAY"},{"id":"temporal-aggregation","value":"false"}]}`
+	if got != types.StringValue(expected) {
+		t.Errorf("dataview_instance_config returned %s", got)
+	}
+}
+
+// TestFrameworkProviderSchema checks the mux server accepts both providers,
+// whose provider schemas must be identical, with and without the settings
+// given through environment variables.
+func TestFrameworkProviderSchema(t *testing.T) {
+	for _, token := range []string{"", "token"} {
+		t.Setenv("GFW_TOKEN", token)
+		server, err := testAccProtoV5ProviderFactories["gfw"]()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := server.GetProviderSchema(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range resp.Diagnostics {
+			t.Errorf("GFW_TOKEN=%q: %s: %s", token, d.Summary, d.Detail)
+		}
+	}
+}
+
+func TestAccFunctions_basic(t *testing.T) {
+	skipBelowTerraform(t, "1.8.0")
+	testAccServer(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+terraform {
+  required_providers {
+    gfw = {
+      source = "hashicorp/gfw"
+    }
+  }
+}
+
+output "dataset_id" {
+  value = provider::gfw::dataset_id("public-global-fishing-effort", "3.0")
+}
+
+output "dataset_version" {
+  value = provider::gfw::parse_dataset_id("public-global-fishing-effort:v3.0").version
+}
+
+output "workspace_id" {
+  value = provider::gfw::workspace_id("Fishing Effort", false)
+}
+
+output "datasets_config" {
+  value = provider::gfw::dataview_instance_config("public-global-fishing-effort:v3.0", "4wings-tiles", {}, null)
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("dataset_id", "public-global-fishing-effort:v3.0"),
+					resource.TestCheckOutput("dataset_version", "3.0"),
+					resource.TestCheckOutput("workspace_id", "fishing_effort"),
+					resource.TestCheckOutput("datasets_config", `{"datasetId":"public-global-fishing-effort:v3.0","endpoint":"4wings-tiles","params":[]}`),
+				),
+			},
+		},
+	})
+}
+
+func runTestFunction(t *testing.T, f function.Function, arguments ...attr.Value) attr.Value {
+	t.Helper()
+	resp := callTestFunction(f, arguments...)
+	if resp.Error != nil {
+		t.Fatal(resp.Error)
+	}
+	return resp.Result.Value()
+}
+
+func callTestFunction(f function.Function, arguments ...attr.Value) *function.RunResponse {
+	definition := function.DefinitionResponse{}
+	f.Definition(context.Background(), function.DefinitionRequest{}, &definition)
+	result, _ := definition.Definition.Return.NewResultData(context.Background())
+	resp := &function.RunResponse{Result: result}
+	f.Run(context.Background(), function.RunRequest{Arguments: function.NewArgumentsData(arguments)}, resp)
+	return resp
+}
+
+// skipBelowTerraform skips acceptance tests using features of newer Terraform
+// versions than the one running them.
+func skipBelowTerraform(t *testing.T, minimum string) {
+	path := os.Getenv("TF_ACC_TERRAFORM_PATH")
+	if os.Getenv("TF_ACC") == "" || path == "" {
+		return
+	}
+	out, err := exec.Command(path, "version", "-json").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Version string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatal(err)
+	}
+	if version.Must(version.NewVersion(v.Version)).LessThan(version.Must(version.NewVersion(minimum))) {
+		t.Skipf("Terraform %s is older than %s", v.Version, minimum)
+	}
+}
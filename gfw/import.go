@@ -0,0 +1,24 @@
+package gfw
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// importStateWithDefaults imports a resource by its ID. Attributes kept in the
+// state only, such as deletion_protection, cannot be read from the API, so
+// they are given their schema default to avoid a diff after the import.
+func importStateWithDefaults(resource func() *schema.Resource) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		for key, s := range resource().Schema {
+			if s.Default == nil {
+				continue
+			}
+			if err := d.Set(key, s.Default); err != nil {
+				return nil, err
+			}
+		}
+		return []*schema.ResourceData{d}, nil
+	}
+}
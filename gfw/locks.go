@@ -0,0 +1,28 @@
+package gfw
+
+import "sync"
+
+// keyedMutex serialises the operations on the same API object, such as the
+// read, modify and patch of the dataview instances of a workspace done by
+// resources applied in parallel.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// Lock locks key and returns the function unlocking it.
+func (k *keyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = map[string]*sync.Mutex{}
+	}
+	lock, ok := k.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		k.locks[key] = lock
+	}
+	k.mu.Unlock()
+
+	lock.Lock()
+	return lock.Unlock
+}
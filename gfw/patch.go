@@ -0,0 +1,75 @@
+package gfw
+
+import (
+	"encoding/json"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// patchFromChanges builds a PATCH body holding only the attributes that differ
+// from the refreshed state. desired is the complete payload built from the
+// configuration and keys maps attribute names to their JSON key. Attributes
+// removed from the configuration are omitted from desired and sent as null.
+func patchFromChanges(d *schema.ResourceData, desired interface{}, keys map[string]string) (api.Patch, error) {
+	return patchFromChangedAttributes(desired, keys, d.HasChange)
+}
+
+// patchFromPlan is patchFromChanges for the resources of the framework
+// provider, comparing the plan with the state.
+func patchFromPlan(plan tfsdk.Plan, state tfsdk.State, desired interface{}, keys map[string]string) (api.Patch, error) {
+	return patchFromChangedAttributes(desired, keys, func(attribute string) bool {
+		path := tftypes.NewAttributePath().WithAttributeName(attribute)
+		planned, _, err := tftypes.WalkAttributePath(plan.Raw, path)
+		if err != nil {
+			return true
+		}
+		prior, _, err := tftypes.WalkAttributePath(state.Raw, path)
+		if err != nil {
+			return true
+		}
+		return !planned.(tftypes.Value).Equal(prior.(tftypes.Value))
+	})
+}
+
+func patchFromChangedAttributes(desired interface{}, keys map[string]string, changed func(attribute string) bool) (api.Patch, error) {
+	body, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+	full := map[string]interface{}{}
+	if err := json.Unmarshal(body, &full); err != nil {
+		return nil, err
+	}
+
+	patch := api.Patch{}
+	for attribute, key := range keys {
+		if changed(attribute) {
+			patch[key] = full[key]
+		}
+	}
+	return patch, nil
+}
+
+// schemaToNullableString returns an optional string attribute of a request
+// body. Empty values are left out on create and sent as null afterwards, so
+// removing the attribute from the configuration clears it on the server.
+func schemaToNullableString(d *schema.ResourceData, key string) api.Nullable[string] {
+	value := d.Get(key).(string)
+	if d.IsNewResource() {
+		return api.OptionalString(value)
+	}
+	return api.NullableString(value)
+}
+
+// modelToNullableString is schemaToNullableString for the attributes of the
+// framework provider, where unset attributes are null.
+func modelToNullableString(value types.String, create bool) api.Nullable[string] {
+	if create {
+		return api.OptionalString(value.ValueString())
+	}
+	return api.NullableString(value.ValueString())
+}
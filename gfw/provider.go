@@ -2,12 +2,32 @@ package gfw
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// Config is the provider meta passed to every resource: the API client
+// together with the provider level settings.
+type Config struct {
+	Client             *api.GFWClient
+	ValidateReferences bool
+	Enums              Enums
+	StrictEnums        bool
+	ViewportMinZoom    float64
+	ViewportMaxZoom    float64
+
+	// InferredEnums are the enums extended with the values in use on the
+	// API, their unknown values are warnings even with StrictEnums.
+	InferredEnums map[string]bool
+
+	references     referenceCache
+	workspaceLocks keyedMutex
+}
+
 // Provider -
 func Provider() *schema.Provider {
 	return &schema.Provider{
@@ -24,18 +44,56 @@ func Provider() *schema.Provider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("GFW_URL", nil),
 			},
+			"validate_references": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GFW_VALIDATE_REFERENCES", true),
+			},
+			"fetch_enums": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GFW_FETCH_ENUMS", false),
+			},
+			"enums_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GFW_ENUMS_FILE", ""),
+			},
+			"strict_enums": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GFW_STRICT_ENUMS", true),
+			},
+			"warn_on_unknown_fields": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GFW_WARN_ON_UNKNOWN_FIELDS", false),
+			},
+			"viewport_min_zoom": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Optional: true,
+				Default:  0,
+			},
+			"viewport_max_zoom": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Optional: true,
+				Default:  22,
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"gfw_action":           resourceAction(),
-			"gfw_resource":         resourceResource(),
-			"gfw_permission":       resourcePermission(),
-			"gfw_role":             resourceRole(),
-			"gfw_role_permissions": resourceRolePermissions(),
-			"gfw_user_group":       resourceUserGroup(),
-			"gfw_user_group_role":  resourceUserGroupRole(),
-			"gfw_dataset":          resourceDataset(),
-			"gfw_dataview":         resourceDataview(),
-			"gfw_workspace":        resourceWorkspace(),
+			"gfw_action":                      resourceAction(),
+			"gfw_resource":                    resourceResource(),
+			"gfw_permission":                  resourcePermission(),
+			"gfw_role":                        resourceRole(),
+			"gfw_role_permissions":            resourceRolePermissions(),
+			"gfw_user_group":                  resourceUserGroup(),
+			"gfw_user_group_role":             resourceUserGroupRole(),
+			"gfw_dataset":                     resourceDataset(),
+			"gfw_dataset_alias":               resourceDatasetAlias(),
+			"gfw_dataset_version":             resourceDatasetVersion(),
+			"gfw_dataview":                    resourceDataview(),
+			"gfw_workspace_copy":              resourceWorkspaceCopy(),
+			"gfw_workspace_dataview_instance": resourceWorkspaceDataviewInstance(),
 		},
 		DataSourcesMap:       map[string]*schema.Resource{},
 		ConfigureContextFunc: providerConfigure,
@@ -47,6 +105,8 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 	url := d.Get("url").(string)
 
+	validateReferences := d.Get("validate_references").(bool)
+
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 
@@ -54,6 +114,64 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	if err != nil {
 		return nil, diag.FromErr(err)
 	}
+	c.ReportUnknownFields = d.Get("warn_on_unknown_fields").(bool)
+
+	enums := defaultEnums()
+	inferred := map[string]bool{}
+	if d.Get("fetch_enums").(bool) {
+		fetched, err := fetchEnums(c)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Unable to fetch enums from the API",
+				Detail:   fmt.Sprintf("Falling back to the built-in enums: %v", err),
+			})
+		} else {
+			enums.merge(fetched)
+			for name, values := range fetched {
+				if len(values) > 0 {
+					inferred[name] = true
+				}
+			}
+		}
+	}
+	if path := d.Get("enums_file").(string); path != "" {
+		file, err := loadEnumsFile(path)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		enums.override(file)
+		for name := range file {
+			delete(inferred, name)
+		}
+	}
 
-	return c, diags
+	minZoom := d.Get("viewport_min_zoom").(float64)
+	maxZoom := d.Get("viewport_max_zoom").(float64)
+	if minZoom > maxZoom {
+		return nil, diag.Errorf("viewport_min_zoom %v is greater than viewport_max_zoom %v", minZoom, maxZoom)
+	}
+
+	return &Config{
+		Client:             c,
+		ValidateReferences: validateReferences,
+		Enums:              enums,
+		StrictEnums:        d.Get("strict_enums").(bool),
+		InferredEnums:      inferred,
+		ViewportMinZoom:    minZoom,
+		ViewportMaxZoom:    maxZoom,
+	}, diags
+}
+
+// unknownFieldsWarning warns about the attributes returned by the API for an
+// object that the provider does not model, they are missing from the state.
+func unknownFieldsWarning(kind string, id string, paths []string) diag.Diagnostics {
+	if len(paths) == 0 {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("The API returned attributes of %s %s unknown to the provider", kind, id),
+		Detail:   fmt.Sprintf("These attributes are not kept in the state: %s", strings.Join(paths, ", ")),
+	}}
 }
@@ -0,0 +1,94 @@
+package gfw
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// frameworkProvider serves, next to the SDKv2 provider, what needs
+// terraform-plugin-framework, such as provider functions, and the resources
+// moved over from the SDKv2 provider. Both are combined by a mux server in
+// main.go.
+type frameworkProvider struct {
+	sdk *schema.Provider
+}
+
+var _ provider.ProviderWithFunctions = &frameworkProvider{}
+
+// NewFrameworkProvider returns the framework provider muxed with sdk, which
+// owns the provider settings: the framework provider declares the same schema
+// and shares its Config once configured.
+func NewFrameworkProvider(sdk *schema.Provider) provider.Provider {
+	return &frameworkProvider{sdk: sdk}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "gfw"
+}
+
+// Schema copies the provider schema served by the SDKv2 provider, the mux
+// server requires them to be identical. Required settings with an
+// environment variable set are served as optional, so it cannot be written by
+// hand.
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema, err := p.sdk.GRPCProvider().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to read the provider schema", err.Error())
+		return
+	}
+	attributes := map[string]providerschema.Attribute{}
+	for _, a := range sdkSchema.Provider.Block.Attributes {
+		switch {
+		case a.Type.Is(tftypes.String):
+			attributes[a.Name] = providerschema.StringAttribute{Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description}
+		case a.Type.Is(tftypes.Bool):
+			attributes[a.Name] = providerschema.BoolAttribute{Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description}
+		case a.Type.Is(tftypes.Number):
+			attributes[a.Name] = providerschema.Float64Attribute{Required: a.Required, Optional: a.Optional, Sensitive: a.Sensitive, Description: a.Description}
+		default:
+			resp.Diagnostics.AddError("Unsupported provider setting", fmt.Sprintf("%s has type %s", a.Name, a.Type))
+		}
+	}
+	resp.Schema = providerschema.Schema{Attributes: attributes}
+}
+
+// Configure shares the Config built by the SDKv2 provider, which the mux
+// server configures first.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	config, ok := p.sdk.Meta().(*Config)
+	if !ok {
+		return
+	}
+	resp.ResourceData = config
+	resp.DataSourceData = config
+}
+
+// Resources returns the resources moved from the SDKv2 provider, which no
+// longer declares them.
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newWorkspaceResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		newDatasetIDFunction,
+		newParseDatasetIDFunction,
+		newWorkspaceIDFunction,
+		newDataviewInstanceConfigFunction,
+	}
+}
@@ -0,0 +1,156 @@
+package gfw
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+var testAccProviderFactories = map[string]func() (*schema.Provider, error){
+	"gfw": func() (*schema.Provider, error) {
+		return Provider(), nil
+	},
+}
+
+// testAccProtoV5ProviderFactories serve the SDKv2 and the framework providers
+// through a mux server, as main.go does.
+var testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"gfw": func() (tfprotov5.ProviderServer, error) {
+		sdkProvider := Provider()
+		muxServer, err := tf5muxserver.NewMuxServer(context.Background(),
+			sdkProvider.GRPCProvider,
+			providerserver.NewProtocol5(NewFrameworkProvider(sdkProvider)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return muxServer.ProviderServer(), nil
+	},
+}
+
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testAccServer starts the fake GFW API and points the provider at it, so the
+// acceptance tests need no credentials.
+func testAccServer(t *testing.T) *fake.Server {
+	s := fake.NewServer()
+	t.Cleanup(s.Close)
+	t.Setenv("GFW_URL", s.URL)
+	t.Setenv("GFW_TOKEN", fake.Token)
+	return s
+}
+
+// testAccSkipBelowTerraform skips an acceptance test run with a Terraform
+// version older than minimum.
+func testAccSkipBelowTerraform(t *testing.T, minimum string) {
+	if os.Getenv(resource.EnvTfAcc) == "" {
+		return
+	}
+	execPath := os.Getenv("TF_ACC_TERRAFORM_PATH")
+	if execPath == "" {
+		execPath = "terraform"
+	}
+	out, err := exec.Command(execPath, "version", "-json").Output()
+	if err != nil {
+		t.Fatalf("unable to read the Terraform version: %v", err)
+	}
+	var v struct {
+		Version string `json:"terraform_version"`
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatal(err)
+	}
+	if version.Must(version.NewVersion(v.Version)).LessThan(version.Must(version.NewVersion(minimum))) {
+		t.Skipf("Terraform %s is older than %s", v.Version, minimum)
+	}
+}
+
+// testAccCheckDestroyed checks that the objects of the given resource type are
+// gone from the server.
+func testAccCheckDestroyed(s *fake.Server, collection string, resourceType string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		for _, rs := range state.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if _, ok := s.Get(collection, rs.Primary.ID); ok {
+				return fmt.Errorf("%s %s still exists", resourceType, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccDisappears deletes the object of a resource behind Terraform's back,
+// the following plan should recreate it.
+func testAccDisappears(s *fake.Server, collection string, name string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found in state", name)
+		}
+		s.Remove(collection, rs.Primary.ID)
+		return nil
+	}
+}
+
+// testAccLifecycle describes the steps shared by the acceptance tests of the
+// resources: create, update, import and recreation once the object is gone.
+type testAccLifecycle struct {
+	// Name is the address of the resource under test, such as gfw_role.test.
+	Name string
+	// Collection holds the objects of the resource on the fake API.
+	Collection string
+	Create     resource.TestStep
+	Update     resource.TestStep
+	// ImportStateId is the import ID when it differs from the resource ID.
+	ImportStateId           string
+	ImportStateVerifyIgnore []string
+	// Steps run after the import, with the resource specific checks.
+	Steps []resource.TestStep
+	// Disappears removes the object behind Terraform's back, by default it
+	// is deleted from Collection.
+	Disappears resource.TestCheckFunc
+}
+
+// testAccLifecycleSteps returns the steps of the lifecycle, the object is
+// removed with the configuration of the update.
+func testAccLifecycleSteps(s *fake.Server, lc testAccLifecycle) []resource.TestStep {
+	disappears := lc.Disappears
+	if disappears == nil {
+		disappears = testAccDisappears(s, lc.Collection, lc.Name)
+	}
+	steps := []resource.TestStep{
+		lc.Create,
+		lc.Update,
+		{
+			ResourceName:            lc.Name,
+			ImportState:             true,
+			ImportStateId:           lc.ImportStateId,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: lc.ImportStateVerifyIgnore,
+		},
+	}
+	steps = append(steps, lc.Steps...)
+	return append(steps, resource.TestStep{
+		Config:             lc.Update.Config,
+		Check:              disappears,
+		ExpectNonEmptyPlan: true,
+	})
+}
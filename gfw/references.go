@@ -0,0 +1,170 @@
+package gfw
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// reference is a dataset or dataview ID used by an attribute of another
+// resource.
+type reference struct {
+	Path string
+	ID   string
+}
+
+// referenceCache keeps the datasets and dataviews resolved by the provider,
+// so a reference used by several resources, or checked on refresh and then on
+// plan, is read once. Missing objects are not kept, they may be created later
+// in the same run. The listing of every dataset is kept too, to find the
+// holders of the aliases.
+type referenceCache struct {
+	mu        sync.Mutex
+	datasets  map[string]*api.Dataset
+	dataviews map[string]bool
+	listing   []api.Dataset
+}
+
+// dataset returns the referenced dataset, nil when it does not exist.
+func (cache *referenceCache) dataset(c *api.GFWClient, id string) (*api.Dataset, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if dataset, ok := cache.datasets[id]; ok {
+		return dataset, nil
+	}
+	dataset, err := c.GetDataset(id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if cache.datasets == nil {
+		cache.datasets = map[string]*api.Dataset{}
+	}
+	cache.datasets[id] = dataset
+	return dataset, nil
+}
+
+// dataviewExists reports whether the referenced dataview exists.
+func (cache *referenceCache) dataviewExists(c *api.GFWClient, id string) (bool, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.dataviews[id] {
+		return true, nil
+	}
+	_, err := c.GetDataview(id)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if cache.dataviews == nil {
+		cache.dataviews = map[string]bool{}
+	}
+	cache.dataviews[id] = true
+	return true, nil
+}
+
+// datasetsByAlias returns the datasets holding alias, from a listing of every
+// dataset read once until forgetDatasetListing is called.
+func (cache *referenceCache) datasetsByAlias(c *api.GFWClient, alias string) ([]api.Dataset, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.listing == nil {
+		datasets, err := c.GetAllDatasets()
+		if err != nil {
+			return nil, err
+		}
+		cache.listing = datasets
+	}
+	holders := []api.Dataset{}
+	for _, d := range cache.listing {
+		if utils.ContainsString(d.Alias, alias) {
+			holders = append(holders, d)
+		}
+	}
+	return holders, nil
+}
+
+// forgetDatasetListing drops the listing once aliases were changed.
+func (cache *referenceCache) forgetDatasetListing() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.listing = nil
+}
+
+// checkDatasetReferences resolves every referenced dataset through the API.
+// Missing datasets are reported as errors and datasets whose status is
+// deprecated or error as warnings. The resolved datasets are returned by ID.
+func checkDatasetReferences(config *Config, refs []reference) (map[string]*api.Dataset, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	datasets := map[string]*api.Dataset{}
+	missing := map[string]bool{}
+
+	for _, ref := range refs {
+		dataset, ok := datasets[ref.ID]
+		if !ok && !missing[ref.ID] {
+			var err error
+			dataset, err = config.references.dataset(config.Client, ref.ID)
+			if err != nil {
+				return datasets, append(diags, diag.FromErr(err)...)
+			}
+			if dataset == nil {
+				missing[ref.ID] = true
+			} else {
+				datasets[ref.ID] = dataset
+			}
+		}
+		if missing[ref.ID] {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Referenced dataset does not exist",
+				Detail:   fmt.Sprintf("%s references dataset %q, which was not found", ref.Path, ref.ID),
+			})
+			continue
+		}
+		if dataset.Status == "deprecated" || dataset.Status == "error" {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Referenced dataset has status %q", dataset.Status),
+				Detail:   fmt.Sprintf("%s references dataset %q, whose status is %q", ref.Path, ref.ID, dataset.Status),
+			})
+		}
+	}
+
+	return datasets, diags
+}
+
+// checkDataviewReferences resolves every referenced dataview through the API
+// and reports the missing ones as errors.
+func checkDataviewReferences(config *Config, refs []reference) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, ref := range refs {
+		exists, err := config.references.dataviewExists(config.Client, ref.ID)
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		if !exists {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Referenced dataview does not exist",
+				Detail:   fmt.Sprintf("%s references dataview %q, which was not found", ref.Path, ref.ID),
+			})
+		}
+	}
+
+	return diags
+}
+
+func isNotFound(err error) bool {
+	if re, ok := err.(api.AppError); ok {
+		return re.Code == api.NotFoundCode
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package gfw
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// References shared by several resources, or checked on refresh and then on
+// plan, are read once per provider run.
+func TestCheckReferencesReadOnce(t *testing.T) {
+	s := fake.NewServer()
+	t.Cleanup(s.Close)
+	if _, err := s.Put(fake.Datasets, map[string]interface{}{"id": "public-mpa:v1", "status": "deprecated"}); err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{Client: s.APIClient()}
+
+	for i := 0; i < 2; i++ {
+		_, diags := checkDatasetReferences(config, []reference{
+			{Path: "config.0.datasets.0", ID: "public-mpa:v1"},
+			{Path: "config.0.datasets.1", ID: "public-mpa:v1"},
+		})
+		if len(diags) != 2 || diags.HasError() {
+			t.Fatalf("expected two deprecation warnings, got %v", diags)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, diags := checkDatasetReferences(config, []reference{{Path: "aoi", ID: "missing:v1"}}); !diags.HasError() {
+			t.Fatal("expected an error for a missing dataset")
+		}
+	}
+
+	gets := map[string]int{}
+	for _, r := range s.Requests() {
+		if r.Method == "GET" {
+			gets[r.Path]++
+		}
+	}
+	if gets["datasets/public-mpa:v1"] != 1 {
+		t.Errorf("existing dataset read %d times, expected once", gets["datasets/public-mpa:v1"])
+	}
+	// Missing datasets may be created later in the run, so they are not kept.
+	if gets["datasets/missing:v1"] != 2 {
+		t.Errorf("missing dataset read %d times, expected twice", gets["datasets/missing:v1"])
+	}
+}
+
+// AOIs that are not <dataset_id>/<area_id> are warned about without reading
+// the API.
+func TestCheckWorkspaceAoiFormat(t *testing.T) {
+	s := fake.NewServer()
+	t.Cleanup(s.Close)
+	config := &Config{Client: s.APIClient()}
+
+	for _, aoi := range []string{"eez-8371", "test-mpa:v1/", "/42"} {
+		if diags := checkWorkspaceAoi(config, aoi); len(diags) != 1 || diags.HasError() {
+			t.Errorf("expected a warning for aoi %q, got %v", aoi, diags)
+		}
+	}
+	if len(s.Requests()) != 0 {
+		t.Errorf("expected no request, got %v", s.Requests())
+	}
+	if diags := checkWorkspaceAoi(config, "missing:v1/42"); !diags.HasError() {
+		t.Error("expected an error for the missing dataset of an aoi")
+	}
+}
+
+// A dataset deleted after the apply fails the plan of the unchanged resources
+// referencing it.
+func TestAccReferences_deletedDataset(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   string
+		expected string
+	}{
+		"dataview":  {config: testAccDataviewConfig("Protected areas", "#ff0000"), expected: `config.datasets.0 references dataset "test-mpa:v1"`},
+		"workspace": {config: testAccReferencesWorkspaceConfig, expected: `aoi references dataset "test-mpa:v1"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := testAccServer(t)
+			if _, err := s.Put(fake.Datasets, map[string]interface{}{"id": "test-mpa:v1", "type": "context-layer:v1", "category": "context-layer"}); err != nil {
+				t.Fatal(err)
+			}
+			resource.Test(t, resource.TestCase{
+				ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: tc.config,
+					},
+					{
+						PreConfig:   func() { s.Remove(fake.Datasets, "test-mpa:v1") },
+						Config:      tc.config,
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(regexp.QuoteMeta(tc.expected)),
+					},
+				},
+			})
+		})
+	}
+}
+
+const testAccReferencesWorkspaceConfig = `
+resource "gfw_workspace" "test" {
+  workspace_id = "test_workspace"
+  name         = "Test workspace"
+  description  = "Workspace used by the acceptance tests"
+  app          = "fishing-map"
+  aoi          = "test-mpa:v1/42"
+}
+`
+
+// The holders of every alias are found in a single listing of the datasets,
+// read again once aliases were changed.
+func TestDatasetsByAliasListsOnce(t *testing.T) {
+	s := fake.NewServer()
+	s.PageSize = 1
+	t.Cleanup(s.Close)
+	for _, d := range []map[string]interface{}{
+		{"id": "public-mpa:v1", "alias": []string{"public-mpa"}},
+		{"id": "public-eez:v1", "alias": []string{"public-eez"}},
+	} {
+		if _, err := s.Put(fake.Datasets, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := &Config{Client: s.APIClient()}
+	listings := func() int {
+		count := 0
+		for _, r := range s.Requests() {
+			if r.Method == "GET" && r.Path == "datasets" {
+				count++
+			}
+		}
+		return count
+	}
+
+	for _, alias := range []string{"public-mpa", "public-eez"} {
+		holders, err := config.references.datasetsByAlias(config.Client, alias)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(holders) != 1 {
+			t.Fatalf("expected one holder of %q, got %v", alias, holders)
+		}
+	}
+	pages := listings()
+	if pages != 2 {
+		t.Fatalf("expected one listing of two pages, got %d requests", pages)
+	}
+	config.references.forgetDatasetListing()
+	if _, err := config.references.datasetsByAlias(config.Client, "public-mpa"); err != nil {
+		t.Fatal(err)
+	}
+	if listings() != 2*pages {
+		t.Errorf("expected the datasets to be listed again, got %d requests", listings())
+	}
+}
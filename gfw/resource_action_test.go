@@ -0,0 +1,43 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAction_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Actions, "gfw_action"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_action.test",
+			Collection: fake.Actions,
+			Create: resource.TestStep{
+				Config: testAccActionConfig("read", "Read access"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_action.test", "name", "read"),
+					resource.TestCheckResourceAttr("gfw_action.test", "description", "Read access"),
+					resource.TestCheckResourceAttrSet("gfw_action.test", "created_at"),
+				),
+			},
+			// Actions cannot be updated, the change replaces it.
+			Update: resource.TestStep{
+				Config: testAccActionConfig("read", "Read only access"),
+				Check:  resource.TestCheckResourceAttr("gfw_action.test", "description", "Read only access"),
+			},
+		}),
+	})
+}
+
+func testAccActionConfig(name string, description string) string {
+	return fmt.Sprintf(`
+resource "gfw_action" "test" {
+  name        = %q
+  description = %q
+}
+`, name, description)
+}
@@ -2,13 +2,18 @@ package gfw
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iancoleman/strcase"
 )
 
 var DATASET_TYPES []string = []string{
@@ -92,6 +97,10 @@ var DATASET_STATUSES []string = []string{
 	"importing",
 }
 
+// DATASET_DELETE_BEHAVIORS are the actions taken when a dataset is destroyed:
+// delete it, mark it deprecated or only remove it from the state.
+var DATASET_DELETE_BEHAVIORS []string = []string{"delete", "deprecate", "abandon"}
+
 var DATASET_CONFIGURATION_GEOMETRY_TYPES []string = []string{"tracks", "polygons", "points"}
 
 var DATASET_CONTEXT_LAYER_FORMATS []string = []string{"csv", "geojson", "pmtile"}
@@ -189,26 +198,44 @@ func resourceDataset() *schema.Resource {
 		ReadContext:   resourceDatasetRead,
 		UpdateContext: resourceDatasetUpdate,
 		DeleteContext: resourceDatasetDelete,
+		CustomizeDiff: resourceDatasetCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStateWithDefaults(resourceDataset),
+		},
 		Schema: map[string]*schema.Schema{
+			"deletion_protection": deletionProtectionSchema(true),
+			"delete_behavior": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "delete",
+				ValidateFunc: validation.StringInSlice(DATASET_DELETE_BEHAVIORS, false),
+			},
+			"strip_aliases_on_deprecate": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"dataset_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"type": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice(DATASET_TYPES, false),
+				Type:     schema.TypeString,
+				Required: true,
 			},
 			"alias": {
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
-				Type:     schema.TypeList,
-				Optional: true,
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				Description: "Aliases of the dataset. When unset they are read from the API without planning changes, leave it unset on the datasets whose aliases are managed by `gfw_dataset_alias`. Removing it from the configuration keeps the aliases of the dataset.",
 			},
 			"description": {
 				Type:         schema.TypeString,
@@ -230,14 +257,12 @@ func resourceDataset() *schema.Resource {
 				Optional: true,
 			},
 			"category": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice(DATASET_CATEGORIES, false),
+				Type:     schema.TypeString,
+				Required: true,
 			},
 			"subcategory": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice(DATASET_SUBCATEGORIES, false),
+				Type:     schema.TypeString,
+				Required: true,
 			},
 			"source": {
 				Type:     schema.TypeString,
@@ -246,17 +271,21 @@ func resourceDataset() *schema.Resource {
 			"status": {
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validation.StringInSlice(DATASET_STATUSES, false),
 			},
+			"created_at": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"related_datasets": {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"type": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validation.StringInSlice(DATASET_TYPES, false),
+							Type:     schema.TypeString,
+							Required: true,
 						},
 						"id": {
 							Type:     schema.TypeString,
@@ -945,7 +974,7 @@ func resourceDataset() *schema.Resource {
 
 func resourceDatasetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	id := d.Get("dataset_id").(string)
@@ -959,7 +988,8 @@ func resourceDatasetCreate(ctx context.Context, d *schema.ResourceData, m interf
 		return diag.FromErr(err)
 	}
 	d.SetId(datasetCreated.ID)
-	resourceDatasetRead(ctx, d, m)
+	diags = append(diags, enumWarnings(m.(*Config), datasetEnumValues(d))...)
+	diags = append(diags, resourceDatasetRead(ctx, d, m)...)
 
 	return diags
 }
@@ -968,34 +998,46 @@ func resourceDatasetRead(ctx context.Context, d *schema.ResourceData, m interfac
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 	datasetId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	dataset, err := c.GetDataset(datasetId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
+	diags = append(diags, unknownFieldsWarning("dataset", d.Id(), dataset.UnknownFields)...)
 
+	if err := datasetToSchema(d, dataset); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+// datasetToSchema sets the attributes of gfw_dataset read from the API, it is
+// shared with gfw_dataset_version.
+func datasetToSchema(d *schema.ResourceData, dataset *api.Dataset) error {
+	d.Set("dataset_id", dataset.ID)
 	d.Set("name", dataset.Name)
 	d.Set("description", dataset.Description)
 	d.Set("created_at", dataset.CreatedAt)
 	d.Set("type", dataset.Type)
 	d.Set("alias", dataset.Alias)
 	d.Set("start_date", dataset.StartDate)
-	if d.Get("end_date") != nil && d.Get("end_date").(string) != "" {
-		d.Set("end_date", dataset.EndDate)
-	}
+	d.Set("end_date", dataset.EndDate)
 	d.Set("unit", dataset.Unit)
 	d.Set("category", dataset.Category)
 	d.Set("subcategory", dataset.Subcategory)
-	if d.Get("status") != nil && d.Get("status").(string) != "" {
-		d.Set("status", dataset.Status)
-	}
+	d.Set("status", dataset.Status)
 	d.Set("source", dataset.Source)
 	d.Set("type", dataset.Type)
 
 	if dataset.Filters != nil {
 		filters := flattenDatasetFilters(*dataset.Filters)
 		if err := d.Set("filters", []interface{}{filters}); err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	} else {
 		d.Set("filters", nil)
@@ -1004,77 +1046,107 @@ func resourceDatasetRead(ctx context.Context, d *schema.ResourceData, m interfac
 	if dataset.Configuration != nil {
 		configuration := flattenDatasetConfiguration(*dataset.Configuration)
 		if err := d.Set("configuration", []interface{}{configuration}); err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	}
 
 	if dataset.Documentation != nil {
 		documentation := flattenDatasetDocumentation(*dataset.Documentation)
 		if err := d.Set("documentation", []interface{}{documentation}); err != nil {
-			return diag.FromErr(err)
+			return err
 		}
 	} else {
 		d.Set("documentation", nil)
 	}
 
 	relatedDatasets := flattenRelatedDatasets(dataset.RelatedDatasets)
-	if err := d.Set("related_datasets", relatedDatasets); err != nil {
-		return diag.FromErr(err)
-	}
-
-	return diags
+	return d.Set("related_datasets", relatedDatasets)
 }
 
 func resourceDatasetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChangesExcept("deletion_protection", "delete_behavior", "strip_aliases_on_deprecate") {
+		return resourceDatasetRead(ctx, d, m)
+	}
 	dataset, err := schemaToDataset(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	datasetId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	err = c.UpdateDataset(datasetId, dataset)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	return resourceDatasetRead(ctx, d, m)
+	diags := enumWarnings(m.(*Config), datasetEnumValues(d))
+	return append(diags, resourceDatasetRead(ctx, d, m)...)
+}
+
+func resourceDatasetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	return validateEnumValues(m.(*Config), datasetEnumValues(d))
+}
+
+func datasetEnumValues(d resourceGetter) []enumValue {
+	values := []enumValue{
+		{Path: "type", Enum: ENUM_DATASET_TYPES, Value: d.Get("type").(string)},
+		{Path: "category", Enum: ENUM_DATASET_CATEGORIES, Value: d.Get("category").(string)},
+		{Path: "subcategory", Enum: ENUM_DATASET_SUBCATEGORIES, Value: d.Get("subcategory").(string)},
+		{Path: "unit", Enum: ENUM_DATASET_UNITS, Value: d.Get("unit").(string)},
+	}
+	for i, rd := range d.Get("related_datasets").([]interface{}) {
+		if rd == nil {
+			continue
+		}
+		values = append(values, enumValue{
+			Path:  fmt.Sprintf("related_datasets.%d.type", i),
+			Enum:  ENUM_DATASET_TYPES,
+			Value: rd.(map[string]interface{})["type"].(string),
+		})
+	}
+	return values
 }
 
 func schemaToDataset(d *schema.ResourceData) (api.CreateDataset, error) {
 	dataset := api.CreateDataset{}
 	dataset.Name = d.Get("name").(string)
 
-	dataset.Description = d.Get("description").(string)
+	dataset.Description = schemaToNullableString(d, "description")
 
 	dataset.Type = d.Get("type").(string)
 
-	dataset.Unit = d.Get("unit").(string)
+	dataset.Unit = schemaToNullableString(d, "unit")
 
 	dataset.Category = d.Get("category").(string)
 
-	dataset.Subcategory = d.Get("subcategory").(string)
+	dataset.Subcategory = schemaToNullableString(d, "subcategory")
 
 	dataset.Status = d.Get("status").(string)
 
-	dataset.Source = d.Get("source").(string)
+	dataset.Source = schemaToNullableString(d, "source")
 
-	dataset.StartDate = d.Get("start_date").(string)
+	dataset.StartDate = schemaToNullableString(d, "start_date")
 
-	dataset.EndDate = d.Get("end_date").(string)
+	dataset.EndDate = schemaToNullableString(d, "end_date")
 
-	if d.Get("alias") != nil {
+	// Aliases may be owned by gfw_dataset_alias, only send them when they
+	// are set from this resource.
+	if d.Get("alias") != nil && (d.IsNewResource() || d.HasChange("alias")) {
 		dataset.Alias = utils.ConvertArrayInterfaceToArrayString(d.Get("alias").([]interface{}))
 	}
 	if d.Get("filters") != nil {
 		filtersList := d.Get("filters").([]interface{})
 		if len(filtersList) > 0 {
-			filters := schemaToDatasetFilters(filtersList[0].(map[string]interface{}))
+			filters := schemaToDatasetFilters(configuredBlock(filtersList, d.GetRawConfig().GetAttr("filters")))
 			dataset.Filters = &filters
 		}
 	}
 	if d.Get("configuration") != nil {
 		configuration := d.Get("configuration").([]interface{})
 		if len(configuration) > 0 {
-			config := schemaToDatasetConfiguration(configuration[0].(map[string]interface{}), d.Get("name").(string))
+			config := schemaToDatasetConfiguration(configuredBlock(configuration, d.GetRawConfig().GetAttr("configuration")), d.Get("name").(string))
+			if !d.IsNewResource() {
+				previous, _ := d.GetChange("configuration")
+				config.NullFields = removedDatasetConfigurationBlocks(previous.([]interface{}), configuration)
+			}
 			dataset.Configuration = &config
 		}
 	}
@@ -1093,6 +1165,88 @@ func schemaToDataset(d *schema.ResourceData) (api.CreateDataset, error) {
 	return dataset, nil
 }
 
+// firstBlock returns the single element of a block. A block written without
+// any attribute is read from the schema as nil.
+func firstBlock(block []interface{}) map[string]interface{} {
+	value, _ := block[0].(map[string]interface{})
+	if value == nil {
+		return map[string]interface{}{}
+	}
+	return value
+}
+
+// configuredBlock returns the single element of a block without the attributes
+// left out of the configuration. Unset numbers read as 0 from the schema and
+// would be sent to the API as 0 instead of being omitted.
+func configuredBlock(block []interface{}, config cty.Value) map[string]interface{} {
+	value := firstBlock(block)
+	if config.IsKnown() && !config.IsNull() && config.LengthInt() > 0 {
+		value = pruneUnconfigured(value, config.Index(cty.NumberIntVal(0))).(map[string]interface{})
+	}
+	return value
+}
+
+// pruneUnconfigured removes the attributes of value that are null in config,
+// descending into nested blocks. The value is returned unchanged when the
+// configuration is not available, as on import.
+func pruneUnconfigured(value interface{}, config cty.Value) interface{} {
+	if !config.IsKnown() || config.IsNull() {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if !config.Type().IsObjectType() {
+			return v
+		}
+		pruned := map[string]interface{}{}
+		for key, e := range v {
+			if !config.Type().HasAttribute(key) {
+				pruned[key] = e
+				continue
+			}
+			attribute := config.GetAttr(key)
+			if attribute.IsNull() {
+				continue
+			}
+			pruned[key] = pruneUnconfigured(e, attribute)
+		}
+		return pruned
+	case []interface{}:
+		if !config.Type().IsListType() || config.LengthInt() != len(v) {
+			return v
+		}
+		list := make([]interface{}, len(v))
+		for i, e := range v {
+			list[i] = pruneUnconfigured(e, config.Index(cty.NumberIntVal(int64(i))))
+		}
+		return list
+	}
+	return value
+}
+
+// removedDatasetConfigurationBlocks returns the JSON keys of the configuration
+// blocks present in the previous state and removed from the configuration.
+func removedDatasetConfigurationBlocks(previous, current []interface{}) []string {
+	if len(previous) == 0 || previous[0] == nil {
+		return nil
+	}
+	currentBlocks := map[string]interface{}{}
+	if len(current) > 0 && current[0] != nil {
+		currentBlocks = current[0].(map[string]interface{})
+	}
+	var removed []string
+	for key, value := range previous[0].(map[string]interface{}) {
+		if block, ok := value.([]interface{}); !ok || len(block) == 0 || key == "api_supported_versions" {
+			continue
+		}
+		if block, ok := currentBlocks[key].([]interface{}); !ok || len(block) == 0 {
+			removed = append(removed, strcase.ToLowerCamel(key))
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
 func schemaToDatasetConfiguration(schema map[string]interface{}, name string) api.DatasetConfiguration {
 	config := api.DatasetConfiguration{}
 
@@ -1105,7 +1259,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["context_layer_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			contextConfig := schemaToContextLayerV1Config(configArray[0].(map[string]interface{}))
+			contextConfig := schemaToContextLayerV1Config(firstBlock(configArray))
 			config.ContextLayerV1 = &contextConfig
 		}
 	}
@@ -1114,7 +1268,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["user_context_layer_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			userContextConfig := schemaToUserContextLayerV1Config(configArray[0].(map[string]interface{}))
+			userContextConfig := schemaToUserContextLayerV1Config(firstBlock(configArray))
 			config.UserContextLayerV1 = &userContextConfig
 		}
 	}
@@ -1123,7 +1277,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["temporal_context_layer_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			temporalContextConfig := schemaToTemporalContextLayerV1Config(configArray[0].(map[string]interface{}))
+			temporalContextConfig := schemaToTemporalContextLayerV1Config(firstBlock(configArray))
 			config.TemporalContextLayerV1 = &temporalContextConfig
 		}
 	}
@@ -1132,7 +1286,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["user_tracks_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			userTracksConfig := schemaToUserTracksV1Config(configArray[0].(map[string]interface{}))
+			userTracksConfig := schemaToUserTracksV1Config(firstBlock(configArray))
 			config.UserTracksV1 = &userTracksConfig
 		}
 	}
@@ -1141,7 +1295,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["pm_tiles_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			pmTilesConfig := schemaToPmTilesV1Config(configArray[0].(map[string]interface{}))
+			pmTilesConfig := schemaToPmTilesV1Config(firstBlock(configArray))
 			config.PmTilesV1 = &pmTilesConfig
 		}
 	}
@@ -1150,7 +1304,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["events_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			eventsConfig := schemaToEventsV1Config(configArray[0].(map[string]interface{}))
+			eventsConfig := schemaToEventsV1Config(firstBlock(configArray))
 			config.EventsV1 = &eventsConfig
 		}
 	}
@@ -1159,7 +1313,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["fourwings_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			fourwingsConfig := schemaToFourwingsV1Config(configArray[0].(map[string]interface{}))
+			fourwingsConfig := schemaToFourwingsV1Config(firstBlock(configArray))
 			config.FourwingsV1 = &fourwingsConfig
 		}
 	}
@@ -1168,7 +1322,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["tracks_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			tracksConfig := schemaToTracksV1Config(configArray[0].(map[string]interface{}))
+			tracksConfig := schemaToTracksV1Config(firstBlock(configArray))
 			config.TracksV1 = &tracksConfig
 		}
 	}
@@ -1177,7 +1331,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["frontend"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			frontendConfig := schemaToFrontendConfig(configArray[0].(map[string]interface{}))
+			frontendConfig := schemaToFrontendConfig(firstBlock(configArray))
 			config.Frontend = &frontendConfig
 		}
 	}
@@ -1186,7 +1340,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["vessels_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			vesselsConfig := schemaToVesselsV1Config(configArray[0].(map[string]interface{}))
+			vesselsConfig := schemaToVesselsV1Config(firstBlock(configArray))
 			config.VesselsV1 = &vesselsConfig
 		}
 	}
@@ -1195,7 +1349,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["insights_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			insightsConfig := schemaToInsightsV1Config(configArray[0].(map[string]interface{}))
+			insightsConfig := schemaToInsightsV1Config(firstBlock(configArray))
 			config.InsightsV1 = &insightsConfig
 		}
 	}
@@ -1204,7 +1358,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["bulk_download_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			bulkDownloadConfig := schemaToBulkDownloadV1Config(configArray[0].(map[string]interface{}))
+			bulkDownloadConfig := schemaToBulkDownloadV1Config(firstBlock(configArray))
 			config.BulkDownloadV1 = &bulkDownloadConfig
 		}
 	}
@@ -1213,7 +1367,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["data_download_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			dataDownloadConfig := schemaToDataDownloadV1Config(configArray[0].(map[string]interface{}))
+			dataDownloadConfig := schemaToDataDownloadV1Config(firstBlock(configArray))
 			config.DataDownloadV1 = &dataDownloadConfig
 		}
 	}
@@ -1222,7 +1376,7 @@ func schemaToDatasetConfiguration(schema map[string]interface{}, name string) ap
 	if val, ok := schema["thumbnails_v1"]; ok {
 		configArray := val.([]interface{})
 		if len(configArray) > 0 {
-			thumbnailsConfig := schemaToThumbnailsV1Config(configArray[0].(map[string]interface{}))
+			thumbnailsConfig := schemaToThumbnailsV1Config(firstBlock(configArray))
 			config.ThumbnailsV1 = &thumbnailsConfig
 		}
 	}
@@ -1356,10 +1510,12 @@ func schemaToFourwingsV1Config(schema map[string]interface{}) api.FourwingsV1Con
 		if len(extraPropsArray) > 0 {
 			extraProps := make([]api.ExtraPropertyPositionTiles, len(extraPropsArray))
 			for i, prop := range extraPropsArray {
-				propMap := prop.(map[string]interface{})
+				propMap, _ := prop.(map[string]interface{})
+				id, _ := propMap["id"].(string)
+				propType, _ := propMap["type"].(string)
 				extraProps[i] = api.ExtraPropertyPositionTiles{
-					ID:   propMap["id"].(string),
-					Type: propMap["type"].(string),
+					ID:   id,
+					Type: propType,
 				}
 			}
 			config.ExtraPropertiesPositionTiles = extraProps
@@ -1548,7 +1704,8 @@ func schemaToInsightsV1Config(schema map[string]interface{}) api.InsightsV1Confi
 		if len(sourcesArray) > 0 {
 			array := make([]api.InsightSources, len(sourcesArray))
 			for i, source := range sourcesArray {
-				array[i] = schemaToDatasetInsightSource(source.(map[string]interface{}))
+				sourceMap, _ := source.(map[string]interface{})
+				array[i] = schemaToDatasetInsightSource(sourceMap)
 			}
 			config.Sources = array
 		}
@@ -1615,11 +1772,10 @@ func schemaToThumbnailsV1Config(schema map[string]interface{}) api.ThumbnailsV1C
 }
 
 func schemaToDatasetInsightSource(schema map[string]interface{}) api.InsightSources {
-	doc := api.InsightSources{
-		ID:      schema["id"].(string),
-		Type:    schema["type"].(string),
-		Insight: schema["insight"].(string),
-	}
+	doc := api.InsightSources{}
+	doc.ID, _ = schema["id"].(string)
+	doc.Type, _ = schema["type"].(string)
+	doc.Insight, _ = schema["insight"].(string)
 
 	return doc
 }
@@ -1639,12 +1795,25 @@ func schemaToRelatedDatasets(schema []interface{}) []api.RelatedDataset {
 func resourceDatasetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
+	if diags := checkDeletionProtection(d, "dataset"); diags.HasError() {
+		return diags
+	}
 	datasetId := d.Id()
 
-	c := m.(*api.GFWClient)
-	_, err := c.DeleteDataset(datasetId)
-	if err != nil {
-		return diag.FromErr(err)
+	c := m.(*Config).Client
+	switch d.Get("delete_behavior").(string) {
+	case "abandon":
+		log.Printf("[INFO] Dataset %s removed from the state and kept in the API", datasetId)
+	case "deprecate":
+		err := c.DeprecateDataset(datasetId, d.Get("strip_aliases_on_deprecate").(bool))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	default:
+		_, err := c.DeleteDataset(datasetId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return diags
@@ -1729,6 +1898,36 @@ func flattenDatasetConfiguration(config api.DatasetConfiguration) interface{} {
 	return a
 }
 
+// expandableValue converts the typed slices built by the flatten functions
+// into the []interface{} values the schemaTo functions get from ResourceData.
+func expandableValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, e := range val {
+			val[k] = expandableValue(e)
+		}
+		return val
+	case []interface{}:
+		for i, e := range val {
+			val[i] = expandableValue(e)
+		}
+		return val
+	case []map[string]interface{}:
+		list := make([]interface{}, len(val))
+		for i, e := range val {
+			list[i] = expandableValue(e)
+		}
+		return list
+	case []string:
+		list := make([]interface{}, len(val))
+		for i, e := range val {
+			list[i] = e
+		}
+		return list
+	}
+	return v
+}
+
 // Flatten functions for nested configurations
 
 func flattenContextLayerV1Config(config api.ContextLayerV1Config) map[string]interface{} {
@@ -2173,6 +2372,20 @@ func flattenDatasetFilters(filters api.DatasetFilters) map[string]interface{} {
 	return result
 }
 
+// datasetFilterConfigs returns every filter declared by a dataset regardless of
+// the group it belongs to.
+func datasetFilterConfigs(filters api.DatasetFilters) []api.FilterConfig {
+	list := []api.FilterConfig{}
+	list = append(list, filters.Fourwings...)
+	list = append(list, filters.Events...)
+	list = append(list, filters.Vessels...)
+	list = append(list, filters.Tracks...)
+	list = append(list, filters.UserTracks...)
+	list = append(list, filters.UserContextLayers...)
+	list = append(list, filters.ContextLayers...)
+	return list
+}
+
 func schemaToDatasetDocumentation(data map[string]interface{}) api.DatasetDocumentation {
 	doc := api.DatasetDocumentation{}
 
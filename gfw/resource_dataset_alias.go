@@ -0,0 +1,193 @@
+package gfw
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceDatasetAlias owns a single dataset alias. The datasets involved
+// should leave their alias attribute unset, it is then only read.
+func resourceDatasetAlias() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Points a dataset alias at a single dataset. Moving it removes it from the datasets holding it before adding it to the target, and gives it back to them if that fails. The `gfw_dataset` resources of the datasets involved should leave `alias` unset, otherwise they plan to undo the move.",
+		CreateContext: resourceDatasetAliasCreate,
+		ReadContext:   resourceDatasetAliasRead,
+		UpdateContext: resourceDatasetAliasUpdate,
+		DeleteContext: resourceDatasetAliasDelete,
+		CustomizeDiff: resourceDatasetAliasCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"alias": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"dataset_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"allow_takeover": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Move the alias even when it is held by a dataset other than the previous target.",
+			},
+			"claimed_by": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Datasets holding the alias.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceDatasetAliasCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	c := m.(*Config).Client
+	var diags diag.Diagnostics
+
+	alias := d.Get("alias").(string)
+	datasetId := d.Get("dataset_id").(string)
+	if !d.Get("allow_takeover").(bool) {
+		if err := checkDatasetAliasUnclaimed(c, alias, datasetId); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err := c.MoveDatasetAlias(alias, datasetId)
+	m.(*Config).references.forgetDatasetListing()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(alias)
+	diags = append(diags, resourceDatasetAliasRead(ctx, d, m)...)
+	return diags
+}
+
+func resourceDatasetAliasRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	alias := d.Id()
+	config := m.(*Config)
+	holders, err := config.references.datasetsByAlias(config.Client, alias)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(holders) == 0 {
+		d.SetId("")
+		return diags
+	}
+
+	datasetId := d.Get("dataset_id").(string)
+	claimedBy := make([]string, len(holders))
+	owned := false
+	for i, h := range holders {
+		claimedBy[i] = h.ID
+		owned = owned || h.ID == datasetId
+	}
+
+	d.Set("alias", alias)
+	d.Set("claimed_by", claimedBy)
+	if !owned {
+		// The alias was moved outside of Terraform, report the new holder so
+		// the next apply moves it back.
+		d.Set("dataset_id", holders[0].ID)
+	}
+	if len(holders) > 1 {
+		detail := fmt.Sprintf("Datasets %v hold the alias, the next apply will keep it only on %q", claimedBy, datasetId)
+		if !d.Get("allow_takeover").(bool) {
+			detail = fmt.Sprintf("Datasets %v hold the alias, set allow_takeover to keep it only on %q", claimedBy, datasetId)
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Alias %q is claimed by more than one dataset", alias),
+			Detail:   detail,
+		})
+	}
+
+	return diags
+}
+
+func resourceDatasetAliasUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Config).Client
+	alias := d.Id()
+	previous, datasetId := d.GetChange("dataset_id")
+	if !d.Get("allow_takeover").(bool) {
+		if err := checkDatasetAliasUnclaimed(c, alias, previous.(string), datasetId.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	err := c.MoveDatasetAlias(alias, datasetId.(string))
+	m.(*Config).references.forgetDatasetListing()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceDatasetAliasRead(ctx, d, m)
+}
+
+func resourceDatasetAliasDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	alias := d.Id()
+
+	c := m.(*Config).Client
+	err := c.RemoveDatasetAlias(d.Get("dataset_id").(string), alias)
+	m.(*Config).references.forgetDatasetListing()
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceDatasetAliasCustomizeDiff plans an update when other datasets hold
+// the alias too and allow_takeover is set, the update removes it from them.
+func resourceDatasetAliasCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.Get("allow_takeover").(bool) {
+		return nil
+	}
+	if len(d.Get("claimed_by").([]interface{})) > 1 {
+		return d.SetNewComputed("claimed_by")
+	}
+	return nil
+}
+
+// checkDatasetAliasUnclaimed fails when the alias is held by a dataset other
+// than the allowed ones, as that dataset is not managed by this resource.
+func checkDatasetAliasUnclaimed(c *api.GFWClient, alias string, allowed ...string) error {
+	holders, err := c.GetDatasetsByAlias(alias)
+	if err != nil {
+		return err
+	}
+	for _, h := range holders {
+		claimed := true
+		for _, id := range allowed {
+			if h.ID == id {
+				claimed = false
+			}
+		}
+		if claimed {
+			return fmt.Errorf("alias %q is already claimed by dataset %q, set allow_takeover to move it to %q", alias, h.ID, allowed[len(allowed)-1])
+		}
+	}
+	return nil
+}
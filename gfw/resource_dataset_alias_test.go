@@ -0,0 +1,134 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDatasetAlias_basic(t *testing.T) {
+	s := testAccServer(t)
+	for _, id := range []string{"test-mpa:v1", "test-mpa:v2"} {
+		if _, err := s.Put(fake.Datasets, map[string]interface{}{"id": id, "alias": []string{}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name: "gfw_dataset_alias.test",
+			Create: resource.TestStep{
+				Config: testAccDatasetAliasConfig("test-mpa:v1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset_alias.test", "dataset_id", "test-mpa:v1"),
+					resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.#", "1"),
+					resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.0", "test-mpa:v1"),
+				),
+			},
+			// Moving the alias removes it from the previous dataset.
+			Update: resource.TestStep{
+				Config: testAccDatasetAliasConfig("test-mpa:v2", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset_alias.test", "dataset_id", "test-mpa:v2"),
+					resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.#", "1"),
+					resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.0", "test-mpa:v2"),
+				),
+			},
+			ImportStateVerifyIgnore: []string{"allow_takeover"},
+			Steps: []resource.TestStep{
+				{
+					// A claim made outside of Terraform is only reported.
+					Config: testAccDatasetAliasConfig("test-mpa:v2", false),
+					Check: func(state *terraform.State) error {
+						return s.APIClient().SetDatasetAlias("test-mpa:v1", []string{"test-mpa"})
+					},
+				},
+				{
+					// allow_takeover plans an update removing it.
+					Config: testAccDatasetAliasConfig("test-mpa:v2", true),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.#", "1"),
+						resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.0", "test-mpa:v2"),
+						func(state *terraform.State) error {
+							return s.APIClient().SetDatasetAlias("test-mpa:v1", []string{"test-mpa"})
+						},
+					),
+					ExpectNonEmptyPlan: true,
+				},
+				{
+					Config: testAccDatasetAliasConfig("test-mpa:v2", true),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.#", "1"),
+						resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.0", "test-mpa:v2"),
+					),
+				},
+			},
+			Disappears: func(state *terraform.State) error {
+				return s.APIClient().SetDatasetAlias("test-mpa:v2", nil)
+			},
+		}),
+	})
+}
+
+func testAccDatasetAliasConfig(datasetId string, allowTakeover bool) string {
+	return fmt.Sprintf(`
+resource "gfw_dataset_alias" "test" {
+  alias          = "test-mpa"
+  dataset_id     = %q
+  allow_takeover = %t
+}
+`, datasetId, allowTakeover)
+}
+
+// Moving an alias between datasets managed by gfw_dataset, which leave alias
+// unset, shows no drift on them.
+func TestAccDatasetAlias_managedDatasets(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Datasets, "gfw_dataset"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatasetAliasManagedConfig("v1"),
+				Check:  resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.0", "test-mpa:v1"),
+			},
+			{
+				Config: testAccDatasetAliasManagedConfig("v2"),
+				Check:  resource.TestCheckResourceAttr("gfw_dataset_alias.test", "claimed_by.0", "test-mpa:v2"),
+			},
+			{
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset.v1", "alias.#", "0"),
+					resource.TestCheckResourceAttr("gfw_dataset.v2", "alias.0", "test-mpa"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDatasetAliasManagedConfig(version string) string {
+	config := ""
+	for _, v := range []string{"v1", "v2"} {
+		config += fmt.Sprintf(`
+resource "gfw_dataset" %[1]q {
+  deletion_protection = false
+  dataset_id          = "test-mpa:%[1]s"
+  name                = "Protected areas %[1]s"
+  type                = "context-layer:v1"
+  description         = "Marine protected areas"
+  category            = "context-layer"
+  subcategory         = "user"
+}
+`, v)
+	}
+	return config + fmt.Sprintf(`
+resource "gfw_dataset_alias" "test" {
+  alias      = "test-mpa"
+  dataset_id = gfw_dataset.%s.id
+}
+`, version)
+}
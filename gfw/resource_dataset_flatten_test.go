@@ -0,0 +1,188 @@
+package gfw
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The flatten and schemaTo functions of datasets must be inverses, otherwise
+// the value read back from the API differs from the configuration and every
+// plan shows a diff. The values are taken through the schema in between, as
+// Terraform does, and compared on their JSON encoding, which is what the API
+// receives.
+
+func TestDatasetConfigurationRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		config := api.DatasetConfiguration{}
+		randomFill(reflect.ValueOf(&config).Elem(), r)
+		got := schemaToDatasetConfiguration(throughSchema(t, "configuration", flattenDatasetConfiguration(config)), "configuration")
+		if !jsonEqual(t, config, got) {
+			return
+		}
+	}
+}
+
+func TestDatasetFiltersRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		filters := api.DatasetFilters{}
+		randomFill(reflect.ValueOf(&filters).Elem(), r)
+		got := schemaToDatasetFilters(throughSchema(t, "filters", flattenDatasetFilters(filters)))
+		if !jsonEqual(t, filters, got) {
+			return
+		}
+	}
+}
+
+// FuzzDatasetResponse decodes arbitrary dataset responses, which must encode
+// to the same JSON after being decoded again and survive a round trip through
+// the schema.
+func FuzzDatasetResponse(f *testing.F) {
+	f.Add([]byte(`{"id":"public-mpa:v1","configuration":{"contextLayerV1":{"idProperty":"id","fields":["name"]}}}`))
+	f.Add([]byte(`{"id":"public-sst:v1","configuration":{"fourwingsV1":{"min":0,"max":30.5,"maxZoom":12,"intervals":["DAY"]}}}`))
+	f.Add([]byte(`{"id":"public-events:v1","filters":{"events":[{"id":"duration","type":"number","min":0,"max":null,"enabled":true}]}}`))
+	f.Add([]byte(`{"id":"public-frontend:v1","configuration":{"frontend":{"min":null,"minPointSize":1,"translate":true}}}`))
+	f.Fuzz(func(t *testing.T, body []byte) {
+		dataset := api.Dataset{}
+		if err := json.Unmarshal(body, &dataset); err != nil {
+			return
+		}
+		encoded, err := json.Marshal(dataset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded := api.Dataset{}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("encoded dataset can not be decoded: %v", err)
+		}
+		if !jsonEqual(t, dataset, decoded) {
+			return
+		}
+		// The configuration and filters are sent back on update.
+		for _, sent := range []interface{}{dataset.Configuration, dataset.Filters} {
+			encoded, err := json.Marshal(sent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var generic interface{}
+			if err := json.Unmarshal(encoded, &generic); err != nil {
+				t.Fatal(err)
+			}
+			if generic == nil {
+				continue
+			}
+			if path, ok := findNull(generic, ""); ok {
+				t.Fatalf("unset field %s encoded as null in %s", path, encoded)
+			}
+		}
+
+		if dataset.Configuration != nil {
+			got := schemaToDatasetConfiguration(throughSchema(t, "configuration", flattenDatasetConfiguration(*dataset.Configuration)), "configuration")
+			jsonEqual(t, *dataset.Configuration, got)
+		}
+		if dataset.Filters != nil {
+			got := schemaToDatasetFilters(throughSchema(t, "filters", flattenDatasetFilters(*dataset.Filters)))
+			jsonEqual(t, *dataset.Filters, got)
+		}
+	})
+}
+
+// throughSchema sets a flattened block as the configuration of a dataset and
+// returns it as schemaToDataset reads it back.
+func throughSchema(t *testing.T, key string, flat interface{}) map[string]interface{} {
+	value := []interface{}{expandableValue(flat)}
+	d := schema.TestResourceDataRaw(t, resourceDataset().Schema, map[string]interface{}{key: value})
+
+	encoded, err := json.Marshal(map[string]interface{}{key: value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := ctyjson.Unmarshal(encoded, resourceDataset().CoreConfigSchema().ImpliedType())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configuredBlock(d.Get(key).([]interface{}), config.GetAttr(key))
+}
+
+// findNull returns the path of the first null in a decoded JSON value. Unset
+// pointers must be omitted, the API reads null as a value to clear.
+func findNull(v interface{}, path string) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return path, true
+	case map[string]interface{}:
+		for key, e := range val {
+			if p, ok := findNull(e, path+"."+key); ok {
+				return p, true
+			}
+		}
+	case []interface{}:
+		for i, e := range val {
+			if p, ok := findNull(e, fmt.Sprintf("%s[%d]", path, i)); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
+
+func jsonEqual(t *testing.T, expected interface{}, got interface{}) bool {
+	e, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(e) != string(g) {
+		t.Errorf("round trip mismatch:\nexpected %s\ngot      %s", e, g)
+		return false
+	}
+	return true
+}
+
+// randomFill sets v to a random value. Pointers and slices are left nil half
+// of the time, so both the set and unset cases are covered.
+func randomFill(v reflect.Value, r *rand.Rand) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString([]string{"", "id", "name", "public-global-fishing-effort:v3.0"}[r.Intn(4)])
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 0)
+	case reflect.Int:
+		v.SetInt(int64(r.Intn(3) * r.Intn(20)))
+	case reflect.Float64:
+		v.SetFloat(float64(r.Intn(3)) * r.Float64() * 100)
+	case reflect.Ptr:
+		if r.Intn(2) == 0 {
+			return
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		randomFill(v.Elem(), r)
+	case reflect.Slice:
+		if r.Intn(2) == 0 {
+			return
+		}
+		n := 1 + r.Intn(3)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := 0; i < n; i++ {
+			randomFill(v.Index(i), r)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Tag.Get("json") == "-" {
+				continue
+			}
+			randomFill(v.Field(i), r)
+		}
+	}
+}
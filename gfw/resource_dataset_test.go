@@ -0,0 +1,87 @@
+package gfw
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccDataset_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Datasets, "gfw_dataset"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_dataset.test",
+			Collection: fake.Datasets,
+			Create: resource.TestStep{
+				Config: testAccDatasetConfig("Protected areas", `end_date = "2024-01-01T00:00:00.000Z"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset.test", "dataset_id", "test-mpa:v1"),
+					resource.TestCheckResourceAttr("gfw_dataset.test", "name", "Protected areas"),
+					resource.TestCheckResourceAttr("gfw_dataset.test", "end_date", "2024-01-01T00:00:00.000Z"),
+					resource.TestCheckResourceAttr("gfw_dataset.test", "configuration.0.context_layer_v1.0.id_property", "mpa_id"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccDatasetConfig("Marine protected areas", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset.test", "name", "Marine protected areas"),
+					resource.TestCheckResourceAttr("gfw_dataset.test", "end_date", ""),
+				),
+			},
+			ImportStateVerifyIgnore: []string{"deletion_protection"},
+		}),
+	})
+}
+
+func TestDatasetReadUnknownFields(t *testing.T) {
+	s := fake.NewServer()
+	defer s.Close()
+	id, err := s.Put(fake.Datasets, map[string]interface{}{
+		"id":            "test-mpa:v1",
+		"name":          "Protected areas",
+		"configuration": map[string]interface{}{"contextLayerV1": map[string]interface{}{"newThing": "polygon"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := s.APIClient()
+	client.ReportUnknownFields = true
+	d := schema.TestResourceDataRaw(t, resourceDataset().Schema, map[string]interface{}{})
+	d.SetId(id)
+
+	diags := resourceDatasetRead(context.Background(), d, &Config{Client: client})
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, "configuration.contextLayerV1.newThing") {
+		t.Errorf("expected a warning about configuration.contextLayerV1.newThing, got %+v", diags)
+	}
+}
+
+func testAccDatasetConfig(name string, extra string) string {
+	return fmt.Sprintf(`
+resource "gfw_dataset" "test" {
+  deletion_protection = false
+  dataset_id          = "test-mpa:v1"
+  name                = %q
+  type                = "context-layer:v1"
+  description         = "Marine protected areas"
+  category            = "context-layer"
+  subcategory         = "user"
+  start_date          = "2020-01-01T00:00:00.000Z"
+  %s
+
+  configuration {
+    context_layer_v1 {
+      id_property = "mpa_id"
+      fields      = ["name", "status"]
+    }
+  }
+}
+`, name, extra)
+}
@@ -0,0 +1,386 @@
+package gfw
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceDatasetVersion creates a dataset by copying the configuration,
+// filters and documentation of a source dataset. Once created the copy is a
+// regular dataset, later changes of the source are not propagated.
+//
+// The schema is the one of gfw_dataset, every attribute is optional and read
+// back from the copy, so the whole dataset is tracked in the state. Only the
+// attributes set in the configuration are reverted when changed outside
+// Terraform.
+func resourceDatasetVersion() *schema.Resource {
+	dataset := resourceDataset()
+	attributes := map[string]*schema.Schema{
+		"source_dataset_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+			Description:  "Dataset copied on create.",
+		},
+		"configuration_overrides": {
+			Type:          schema.TypeMap,
+			Optional:      true,
+			Elem:          &schema.Schema{Type: schema.TypeString},
+			ValidateFunc:  validateDatasetConfigurationOverrides,
+			ConflictsWith: []string{"configuration"},
+			Description:   "Configuration attributes set on top of the copied configuration, keyed by `<block>.<attribute>` such as `fourwings_v1.table`. Lists are given as comma separated values.",
+		},
+	}
+	for name, attribute := range dataset.Schema {
+		if name != "dataset_id" && !attribute.Computed && attribute.Default == nil {
+			attribute.Required = false
+			attribute.Optional = true
+			attribute.Computed = true
+		}
+		attributes[name] = attribute
+	}
+	attributes["dataset_id"].ValidateFunc = validation.StringIsNotEmpty
+
+	return &schema.Resource{
+		Description:   "Dataset created by copying a source dataset. The attributes are the ones of `gfw_dataset` and are read back from the copy. The attributes set in the configuration replace the copied values and are reverted when changed outside Terraform, the ones left unset keep the values of the copy.",
+		CreateContext: resourceDatasetVersionCreate,
+		ReadContext:   resourceDatasetVersionRead,
+		UpdateContext: resourceDatasetVersionUpdate,
+		DeleteContext: resourceDatasetDelete,
+		CustomizeDiff: resourceDatasetVersionCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDatasetVersionImport,
+		},
+		Schema:   attributes,
+		Timeouts: dataset.Timeouts,
+	}
+}
+
+func resourceDatasetVersionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	c := m.(*Config).Client
+	var diags diag.Diagnostics
+
+	source, err := c.GetDataset(d.Get("source_dataset_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dataset := api.NewCreateDataset(*source)
+	dataset.ID = d.Get("dataset_id").(string)
+	// The status and the aliases belong to the source, the API assigns the
+	// initial status of the copy unless it is set in the configuration.
+	dataset.Status = ""
+	dataset.Alias = nil
+	if err := schemaToDatasetVersion(d, &dataset); err != nil {
+		return diag.FromErr(err)
+	}
+
+	datasetCreated, err := c.CreateDataset(dataset)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(datasetCreated.ID)
+	diags = append(diags, resourceDatasetVersionRead(ctx, d, m)...)
+	return diags
+}
+
+func resourceDatasetVersionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	datasetId := d.Id()
+	c := m.(*Config).Client
+	dataset, err := c.GetDataset(datasetId)
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+	diags = append(diags, unknownFieldsWarning("dataset", d.Id(), dataset.UnknownFields)...)
+
+	if err := datasetToSchema(d, dataset); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	overrides := map[string]interface{}{}
+	for path := range d.Get("configuration_overrides").(map[string]interface{}) {
+		if value, ok := datasetConfigurationOverrideValue(dataset.Configuration, path); ok {
+			overrides[path] = value
+		}
+	}
+	if err := d.Set("configuration_overrides", overrides); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return diags
+}
+
+func resourceDatasetVersionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChangesExcept("deletion_protection", "delete_behavior", "strip_aliases_on_deprecate") {
+		return resourceDatasetVersionRead(ctx, d, m)
+	}
+	c := m.(*Config).Client
+	datasetId := d.Id()
+	current, err := c.GetDataset(datasetId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	dataset := api.NewCreateDataset(*current)
+	dataset.ID = ""
+	if err := schemaToDatasetVersion(d, &dataset); err != nil {
+		return diag.FromErr(err)
+	}
+	err = c.UpdateDataset(datasetId, dataset)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceDatasetVersionRead(ctx, d, m)
+}
+
+// The configuration read back from the API changes with the overrides.
+func resourceDatasetVersionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && d.HasChange("configuration_overrides") {
+		return d.SetNewComputed("configuration")
+	}
+	return nil
+}
+
+// The import ID is <source_dataset_id>/<dataset_id>, the source can not be
+// read from the copied dataset.
+func resourceDatasetVersionImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("unexpected ID %q, expected <source_dataset_id>/<dataset_id>", d.Id())
+	}
+	d.SetId(parts[1])
+	if err := d.Set("source_dataset_id", parts[0]); err != nil {
+		return nil, err
+	}
+	return importStateWithDefaults(resourceDatasetVersion)(ctx, d, m)
+}
+
+// schemaToDatasetVersion applies the attributes set in the configuration on
+// top of the copied dataset.
+func schemaToDatasetVersion(d *schema.ResourceData, dataset *api.CreateDataset) error {
+	configured, err := schemaToDataset(d)
+	if err != nil {
+		return err
+	}
+	// Blocks left out of the configuration are empty lists instead of null.
+	raw := d.GetRawConfig()
+	isSet := func(key string) bool {
+		v := raw.GetAttr(key)
+		if v.IsNull() {
+			return false
+		}
+		if v.IsKnown() && v.CanIterateElements() {
+			return v.LengthInt() > 0
+		}
+		return true
+	}
+	if isSet("name") {
+		dataset.Name = configured.Name
+	}
+	if isSet("description") {
+		dataset.Description = configured.Description
+	}
+	if isSet("type") {
+		dataset.Type = configured.Type
+	}
+	if isSet("alias") {
+		dataset.Alias = configured.Alias
+	}
+	if isSet("start_date") {
+		dataset.StartDate = configured.StartDate
+	}
+	if isSet("end_date") {
+		dataset.EndDate = configured.EndDate
+	}
+	if isSet("unit") {
+		dataset.Unit = configured.Unit
+	}
+	if isSet("status") {
+		dataset.Status = configured.Status
+	}
+	if isSet("category") {
+		dataset.Category = configured.Category
+	}
+	if isSet("subcategory") {
+		dataset.Subcategory = configured.Subcategory
+	}
+	if isSet("source") {
+		dataset.Source = configured.Source
+	}
+	if isSet("configuration") {
+		dataset.Configuration = configured.Configuration
+	}
+	if isSet("related_datasets") {
+		dataset.RelatedDatasets = configured.RelatedDatasets
+	}
+	if isSet("filters") {
+		dataset.Filters = configured.Filters
+	}
+	if isSet("documentation") {
+		dataset.Documentation = configured.Documentation
+	}
+	overrides := d.Get("configuration_overrides").(map[string]interface{})
+	if len(overrides) > 0 {
+		config, err := applyDatasetConfigurationOverrides(dataset.Configuration, overrides, dataset.Name)
+		if err != nil {
+			return err
+		}
+		dataset.Configuration = config
+	}
+	return nil
+}
+
+// datasetConfigurationOverrideSchema resolves an override path such as
+// fourwings_v1.table to the gfw_dataset configuration attribute it sets.
+func datasetConfigurationOverrideSchema(path string) ([]string, *schema.Schema, error) {
+	parts := strings.Split(path, ".")
+	attributes := resourceDataset().Schema["configuration"].Elem.(*schema.Resource).Schema
+	if len(parts) == 2 {
+		block, ok := attributes[parts[0]]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown configuration block %q in override %q", parts[0], path)
+		}
+		resource, ok := block.Elem.(*schema.Resource)
+		if !ok {
+			return nil, nil, fmt.Errorf("configuration attribute %q in override %q is not a block", parts[0], path)
+		}
+		attributes = resource.Schema
+	} else if len(parts) != 1 {
+		return nil, nil, fmt.Errorf("expected override %q to be <block>.<attribute>", path)
+	}
+
+	attribute, ok := attributes[parts[len(parts)-1]]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown configuration attribute in override %q", path)
+	}
+	switch attribute.Type {
+	case schema.TypeString, schema.TypeInt, schema.TypeFloat, schema.TypeBool:
+	case schema.TypeList:
+		if elem, ok := attribute.Elem.(*schema.Schema); !ok || elem.Type != schema.TypeString {
+			return nil, nil, fmt.Errorf("configuration attribute in override %q can not be overridden", path)
+		}
+	default:
+		return nil, nil, fmt.Errorf("configuration attribute in override %q can not be overridden", path)
+	}
+	return parts, attribute, nil
+}
+
+// convertDatasetConfigurationOverride parses an override value into the type
+// of its attribute. Lists are given as comma separated values.
+func convertDatasetConfigurationOverride(value string, attribute *schema.Schema) (interface{}, error) {
+	switch attribute.Type {
+	case schema.TypeInt:
+		return strconv.Atoi(value)
+	case schema.TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	case schema.TypeBool:
+		return strconv.ParseBool(value)
+	case schema.TypeList:
+		list := []interface{}{}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+		return list, nil
+	}
+	return value, nil
+}
+
+func validateDatasetConfigurationOverrides(i interface{}, k string) (warnings []string, errors []error) {
+	overrides, ok := i.(map[string]interface{})
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be map", k))
+		return warnings, errors
+	}
+	for path, value := range overrides {
+		_, attribute, err := datasetConfigurationOverrideSchema(path)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if _, err := convertDatasetConfigurationOverride(value.(string), attribute); err != nil {
+			errors = append(errors, fmt.Errorf("invalid value for override %q: %v", path, err))
+		}
+	}
+	return warnings, errors
+}
+
+func applyDatasetConfigurationOverrides(config *api.DatasetConfiguration, overrides map[string]interface{}, name string) (*api.DatasetConfiguration, error) {
+	flat := map[string]interface{}{}
+	if config != nil {
+		flat = expandableValue(flattenDatasetConfiguration(*config)).(map[string]interface{})
+	}
+	for path, value := range overrides {
+		parts, attribute, err := datasetConfigurationOverrideSchema(path)
+		if err != nil {
+			return nil, err
+		}
+		v, err := convertDatasetConfigurationOverride(value.(string), attribute)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for override %q: %v", path, err)
+		}
+		if len(parts) == 1 {
+			flat[parts[0]] = v
+			continue
+		}
+		block, _ := flat[parts[0]].([]interface{})
+		if len(block) == 0 {
+			block = []interface{}{map[string]interface{}{}}
+			flat[parts[0]] = block
+		}
+		block[0].(map[string]interface{})[parts[1]] = v
+	}
+
+	result := schemaToDatasetConfiguration(flat, name)
+	return &result, nil
+}
+
+func datasetConfigurationOverrideValue(config *api.DatasetConfiguration, path string) (string, bool) {
+	if config == nil {
+		return "", false
+	}
+	var value interface{} = expandableValue(flattenDatasetConfiguration(*config))
+	for _, part := range strings.Split(path, ".") {
+		mp, isMap := value.(map[string]interface{})
+		if !isMap {
+			if list, isList := value.([]interface{}); isList && len(list) > 0 {
+				mp, isMap = list[0].(map[string]interface{})
+			}
+		}
+		if !isMap {
+			return "", false
+		}
+		v, ok := mp[part]
+		if !ok {
+			return "", false
+		}
+		value = v
+	}
+
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		return strings.Join(utils.ConvertArrayInterfaceToArrayString(v), ","), true
+	}
+	return fmt.Sprint(value), true
+}
@@ -0,0 +1,182 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDatasetVersion_basic(t *testing.T) {
+	s := testAccServer(t)
+	_, err := s.Put(fake.Datasets, map[string]interface{}{
+		"id":          "test-mpa:v1",
+		"name":        "Protected areas",
+		"description": "Marine protected areas",
+		"type":        "context-layer:v1",
+		"category":    "context-layer",
+		"subcategory": "user",
+		"status":      "deprecated",
+		"configuration": map[string]interface{}{
+			"idProperty": "mpa_id",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Datasets, "gfw_dataset_version"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_dataset_version.test",
+			Collection: fake.Datasets,
+			Create: resource.TestStep{
+				Config: testAccDatasetVersionConfig("Protected areas 2024", "2024-01-01T00:00:00.000Z"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "dataset_id", "test-mpa:v2"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "name", "Protected areas 2024"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "description", "Marine protected areas"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "type", "context-layer:v1"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "status", ""),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccDatasetVersionConfig("Protected areas 2025", "2025-01-01T00:00:00.000Z"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "name", "Protected areas 2025"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "end_date", "2025-01-01T00:00:00.000Z"),
+				),
+			},
+			ImportStateId:           "test-mpa:v1/test-mpa:v2",
+			ImportStateVerifyIgnore: []string{"deletion_protection"},
+			Steps: []resource.TestStep{
+				{
+					Config: testAccDatasetVersionStatusConfig("done"),
+					Check:  resource.TestCheckResourceAttr("gfw_dataset_version.status", "status", "done"),
+				},
+			},
+		}),
+	})
+}
+
+func testAccDatasetVersionConfig(name string, endDate string) string {
+	return fmt.Sprintf(`
+resource "gfw_dataset_version" "test" {
+  deletion_protection = false
+  source_dataset_id   = "test-mpa:v1"
+  dataset_id          = "test-mpa:v2"
+  name                = %q
+  end_date            = %q
+}
+`, name, endDate)
+}
+
+func testAccDatasetVersionStatusConfig(status string) string {
+	return testAccDatasetVersionConfig("Protected areas 2025", "2025-01-01T00:00:00.000Z") + fmt.Sprintf(`
+resource "gfw_dataset_version" "status" {
+  deletion_protection = false
+  source_dataset_id   = "test-mpa:v1"
+  dataset_id          = "test-mpa:v3"
+  status              = %q
+}
+`, status)
+}
+
+func TestAccDatasetVersion_tracksCopy(t *testing.T) {
+	s := testAccServer(t)
+	_, err := s.Put(fake.Datasets, map[string]interface{}{
+		"id":          "test-mpa:v1",
+		"name":        "Protected areas",
+		"description": "Marine protected areas",
+		"type":        "context-layer:v1",
+		"category":    "context-layer",
+		"subcategory": "user",
+		"source":      "GFW",
+		"configuration": map[string]interface{}{
+			"contextLayerV1": map[string]interface{}{
+				"idProperty": "mpa_id",
+				"srid":       "4326",
+			},
+		},
+		"documentation": map[string]interface{}{
+			"provider": "WDPA",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changeCopy := func(key string, value interface{}) func() {
+		return func() {
+			copied, ok := s.Get(fake.Datasets, "test-mpa:v2")
+			if !ok {
+				t.Fatal("dataset test-mpa:v2 not found")
+			}
+			changed := map[string]interface{}{}
+			for k, v := range copied {
+				changed[k] = v
+			}
+			changed[key] = value
+			if _, err := s.Put(fake.Datasets, changed); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Datasets, "gfw_dataset_version"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatasetVersionOverridesConfig("3857"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "source", "GFW"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "documentation.0.provider", "WDPA"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "configuration.0.context_layer_v1.0.id_property", "mpa_id"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "configuration.0.context_layer_v1.0.srid", "3857"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "configuration_overrides.context_layer_v1.srid", "3857"),
+				),
+			},
+			{
+				// The name is set in the configuration, a change made outside
+				// Terraform is planned to be reverted.
+				PreConfig:          changeCopy("name", "Renamed"),
+				Config:             testAccDatasetVersionOverridesConfig("3857"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccDatasetVersionOverridesConfig("3857"),
+				Check:  resource.TestCheckResourceAttr("gfw_dataset_version.test", "name", "Protected areas 2024"),
+			},
+			{
+				// The source is not configured, the value of the copy is
+				// read into the state.
+				PreConfig:    changeCopy("source", "WDPA"),
+				RefreshState: true,
+				Check:        resource.TestCheckResourceAttr("gfw_dataset_version.test", "source", "WDPA"),
+			},
+			{
+				Config: testAccDatasetVersionOverridesConfig("900913"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "configuration.0.context_layer_v1.0.id_property", "mpa_id"),
+					resource.TestCheckResourceAttr("gfw_dataset_version.test", "configuration.0.context_layer_v1.0.srid", "900913"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDatasetVersionOverridesConfig(srid string) string {
+	return fmt.Sprintf(`
+resource "gfw_dataset_version" "test" {
+  deletion_protection = false
+  source_dataset_id   = "test-mpa:v1"
+  dataset_id          = "test-mpa:v2"
+  name                = "Protected areas 2024"
+
+  configuration_overrides = {
+    "context_layer_v1.srid" = %q
+  }
+}
+`, srid)
+}
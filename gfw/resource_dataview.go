@@ -3,7 +3,10 @@ package gfw
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
@@ -36,6 +39,11 @@ var DATAVIEW_CONFIG_TYPES []string = []string{
 	"VESSEL_EVENTS_SHAPES",
 	"VESSEL_EVENTS",
 }
+var DATAVIEW_CLUSTER_MAX_ZOOM_LEVELS []string = []string{"default", "encounter", "fishing", "gap", "loitering", "port_visit"}
+
+// DATAVIEW_FILTER_TYPES are the JSON types the values of a filter block are
+// sent as.
+var DATAVIEW_FILTER_TYPES []string = []string{"string", "number", "boolean"}
 
 func resourceDataview() *schema.Resource {
 	return &schema.Resource{
@@ -43,10 +51,16 @@ func resourceDataview() *schema.Resource {
 		ReadContext:   resourceDataviewRead,
 		UpdateContext: resourceDataviewUpdate,
 		DeleteContext: resourceDataviewDelete,
+		CustomizeDiff: resourceDataviewCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: importStateWithDefaults(resourceDataview),
+		},
 		Schema: map[string]*schema.Schema{
+			"deletion_protection": deletionProtectionSchema(false),
 			"slug": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"name": {
 				Type:     schema.TypeString,
@@ -57,14 +71,12 @@ func resourceDataview() *schema.Resource {
 				Required: true,
 			},
 			"category": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice(DATAVIEW_TYPES, false),
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 			"app": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice(DATAVIEW_APPS, false),
+				Type:     schema.TypeString,
+				Required: true,
 			},
 			"datasets_config": {
 				Type:     schema.TypeList,
@@ -134,9 +146,8 @@ func resourceDataview() *schema.Resource {
 							},
 						},
 						"type": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validation.StringInSlice(DATAVIEW_CONFIG_TYPES, false),
+							Type:     schema.TypeString,
+							Optional: true,
 						},
 						"aggregation_operation": {
 							Type:     schema.TypeString,
@@ -160,14 +171,63 @@ func resourceDataview() *schema.Resource {
 							Optional: true,
 						},
 						"cluster_max_zoom_levels": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validation.StringIsJSON,
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validation.StringIsJSON,
+							ConflictsWith: []string{"config.0.cluster_max_zoom_level"},
+						},
+						"cluster_max_zoom_level": {
+							Type:          schema.TypeSet,
+							Optional:      true,
+							ConflictsWith: []string{"config.0.cluster_max_zoom_levels"},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringInSlice(DATAVIEW_CLUSTER_MAX_ZOOM_LEVELS, false),
+									},
+									"zoom": {
+										Type:         schema.TypeInt,
+										Required:     true,
+										ValidateFunc: validation.IntBetween(0, 22),
+									},
+								},
+							},
 						},
 						"filters": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validation.StringIsJSON,
+							Type:          schema.TypeString,
+							Optional:      true,
+							ValidateFunc:  validation.StringIsJSON,
+							ConflictsWith: []string{"config.0.filter"},
+						},
+						"filter": {
+							Type:          schema.TypeSet,
+							Optional:      true,
+							ConflictsWith: []string{"config.0.filters"},
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:         schema.TypeString,
+										Required:     true,
+										ValidateFunc: validation.StringIsNotEmpty,
+									},
+									"values": {
+										Type:     schema.TypeList,
+										Required: true,
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+									"type": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Default:      "string",
+										Description:  "JSON type the values are sent as, `string`, `number` or `boolean`.",
+										ValidateFunc: validation.StringInSlice(DATAVIEW_FILTER_TYPES, false),
+									},
+								},
+							},
 						},
 					},
 				},
@@ -194,7 +254,7 @@ func resourceDataview() *schema.Resource {
 
 func resourceDataviewCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	dataview, err := schemaToDataview(d)
@@ -206,7 +266,8 @@ func resourceDataviewCreate(ctx context.Context, d *schema.ResourceData, m inter
 		return diag.FromErr(err)
 	}
 	d.SetId(strconv.Itoa(dataviewCreated.ID))
-	resourceDataviewRead(ctx, d, m)
+	diags = append(diags, enumWarnings(m.(*Config), dataviewEnumValues(d))...)
+	diags = append(diags, resourceDataviewRead(ctx, d, m)...)
 	return diags
 }
 
@@ -214,11 +275,16 @@ func resourceDataviewRead(ctx context.Context, d *schema.ResourceData, m interfa
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 	dataviewId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	dataview, err := c.GetDataview(dataviewId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
+	diags = append(diags, unknownFieldsWarning("dataview", d.Id(), dataview.UnknownFields)...)
 
 	d.Set("name", dataview.Name)
 	d.Set("description", dataview.Description)
@@ -229,10 +295,14 @@ func resourceDataviewRead(ctx context.Context, d *schema.ResourceData, m interfa
 	d.Set("category", dataview.Category)
 
 	if dataview.Config != nil {
-		configuration := flattenDataviewConfiguration(*dataview.Config)
+		configuration := flattenDataviewConfiguration(*dataview.Config, dataviewConfigHasBlock(d, "filter"), dataviewConfigHasBlock(d, "cluster_max_zoom_level"))
 		if err := d.Set("config", []interface{}{configuration}); err != nil {
 			return diag.FromErr(err)
 		}
+		if m.(*Config).ValidateReferences {
+			_, refDiags := checkDatasetReferences(m.(*Config), dataviewDatasetReferences(d.Get("config").([]interface{})))
+			diags = append(diags, diagsWarnings(refDiags)...)
+		}
 	}
 	if dataview.InfoConfig != nil {
 		jsonStr, err := json.Marshal(dataview.InfoConfig)
@@ -284,70 +354,62 @@ func resourceDataviewUpdate(ctx context.Context, d *schema.ResourceData, m inter
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	dataview.Slug = ""
-	dataviewId := d.Id()
-	c := m.(*api.GFWClient)
-	err = c.UpdateDataview(dataviewId, dataview)
+	patch, err := patchFromChanges(d, dataview, dataviewPatchKeys)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	return resourceDataviewRead(ctx, d, m)
+	if len(patch) > 0 {
+		err = m.(*Config).Client.UpdateDataview(d.Id(), patch)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	diags := enumWarnings(m.(*Config), dataviewEnumValues(d))
+	return append(diags, resourceDataviewRead(ctx, d, m)...)
 }
 
+// schemaToDataview returns the complete desired state of the dataview.
 func schemaToDataview(d *schema.ResourceData) (api.CreateDataview, error) {
 	dataview := api.CreateDataview{}
-	if d.HasChange("name") {
-		dataview.Name = d.Get("name").(string)
-	}
-	if d.HasChange("description") {
-		dataview.Description = d.Get("description").(string)
-	}
-	if d.HasChange("app") {
-		dataview.App = d.Get("app").(string)
-	}
-	if d.HasChange("category") {
-		dataview.Category = d.Get("category").(string)
-	}
-	if d.HasChange("slug") {
-		dataview.Slug = d.Get("slug").(string)
-	}
-
-	if d.HasChange("config") && d.Get("config") != nil {
-		configuration := d.Get("config").([]interface{})
-		if len(configuration) > 0 {
-			config, err := schemaToDataviewConfiguration(configuration[0].(map[string]interface{}))
-			if err != nil {
-				return api.CreateDataview{}, err
-			}
-			dataview.Config = &config
+	dataview.Name = d.Get("name").(string)
+	dataview.Description = schemaToNullableString(d, "description")
+	dataview.App = d.Get("app").(string)
+	dataview.Category = schemaToNullableString(d, "category")
+	dataview.Slug = d.Get("slug").(string)
+
+	configuration := d.Get("config").([]interface{})
+	if len(configuration) > 0 && configuration[0] != nil {
+		config, err := schemaToDataviewConfiguration(configuration[0].(map[string]interface{}))
+		if err != nil {
+			return api.CreateDataview{}, err
 		}
+		dataview.Config = &config
 	}
-	if d.HasChange("info_config") && d.Get("info_config") != nil {
+	if v := d.Get("info_config").(string); v != "" {
 		var obj map[string]interface{}
-		err := json.Unmarshal([]byte(d.Get("info_config").(string)), &obj)
+		err := json.Unmarshal([]byte(v), &obj)
 		if err != nil {
 			return api.CreateDataview{}, err
 		}
 		dataview.InfoConfig = &obj
 	}
-	if d.HasChange("filters_config") && d.Get("filters_config") != nil {
+	if v := d.Get("filters_config").(string); v != "" {
 		var obj map[string]interface{}
-		err := json.Unmarshal([]byte(d.Get("filters_config").(string)), &obj)
+		err := json.Unmarshal([]byte(v), &obj)
 		if err != nil {
 			return api.CreateDataview{}, err
 		}
 		dataview.FiltersConfig = &obj
 	}
-	if d.HasChange("events_config") && d.Get("events_config") != nil {
+	if v := d.Get("events_config").(string); v != "" {
 		var obj map[string]interface{}
-		err := json.Unmarshal([]byte(d.Get("events_config").(string)), &obj)
+		err := json.Unmarshal([]byte(v), &obj)
 		if err != nil {
 			return api.CreateDataview{}, err
 		}
 		dataview.EventsConfig = &obj
 	}
-	if d.HasChange("datasets_config") && d.Get("datasets_config") != nil {
-		list := d.Get("datasets_config").([]interface{})
+	if list := d.Get("datasets_config").([]interface{}); len(list) > 0 {
 		datasetsConfig := make([]map[string]interface{}, len(list))
 		for i, m := range list {
 			var obj map[string]interface{}
@@ -364,6 +426,20 @@ func schemaToDataview(d *schema.ResourceData) (api.CreateDataview, error) {
 	return dataview, nil
 }
 
+// dataviewPatchKeys maps the attributes that can be updated in place to their
+// JSON key in the PATCH body.
+var dataviewPatchKeys = map[string]string{
+	"name":            "name",
+	"description":     "description",
+	"app":             "app",
+	"category":        "category",
+	"config":          "config",
+	"info_config":     "infoConfig",
+	"events_config":   "eventsConfig",
+	"filters_config":  "filtersConfig",
+	"datasets_config": "datasetsConfig",
+}
+
 func schemaToDataviewConfiguration(schema map[string]interface{}) (api.DataviewConfiguration, error) {
 	config := api.DataviewConfiguration{
 		Type:                 schema["type"].(string),
@@ -375,6 +451,20 @@ func schemaToDataviewConfiguration(schema map[string]interface{}) (api.DataviewC
 		Breaks:               utils.ConvertArrayInterfaceToArrayFloat(schema["breaks"].([]interface{})),
 		AggregationOperation: schema["aggregation_operation"].(string),
 	}
+	if val, ok := schema["cluster_max_zoom_level"]; ok {
+		if levels := schemaToDataviewClusterMaxZoomLevels(val); len(levels) > 0 {
+			config.ClusterMaxZoomLevels = &levels
+		}
+	}
+	if val, ok := schema["filter"]; ok {
+		filters, err := schemaToDataviewFilters(val)
+		if err != nil {
+			return api.DataviewConfiguration{}, err
+		}
+		if len(filters) > 0 {
+			config.Filters = &filters
+		}
+	}
 	if val, ok := schema["cluster_max_zoom_levels"]; ok && val != "" {
 		var obj map[string]interface{}
 		err := json.Unmarshal([]byte(val.(string)), &obj)
@@ -409,6 +499,62 @@ func schemaToDataviewConfiguration(schema map[string]interface{}) (api.DataviewC
 	return config, nil
 }
 
+func schemaToDataviewClusterMaxZoomLevels(set interface{}) map[string]interface{} {
+	levels := map[string]interface{}{}
+	for _, l := range set.(*schema.Set).List() {
+		level := l.(map[string]interface{})
+		levels[level["name"].(string)] = level["zoom"].(int)
+	}
+	return levels
+}
+
+// schemaToDataviewFilters returns the filter values as their JSON type.
+func schemaToDataviewFilters(set interface{}) (map[string]interface{}, error) {
+	filters := map[string]interface{}{}
+	for _, f := range set.(*schema.Set).List() {
+		filter := f.(map[string]interface{})
+		values, err := dataviewFilterValues(filter)
+		if err != nil {
+			return nil, err
+		}
+		filters[filter["id"].(string)] = values
+	}
+	return filters, nil
+}
+
+// dataviewFilterValues converts the values of a filter block, written as
+// strings, to the type of the block.
+func dataviewFilterValues(filter map[string]interface{}) ([]interface{}, error) {
+	id := filter["id"].(string)
+	filterType, _ := filter["type"].(string)
+	raw := filter["values"].([]interface{})
+	values := make([]interface{}, len(raw))
+	for i, value := range raw {
+		// Values unknown at plan time are checked on apply.
+		v, ok := value.(string)
+		if !ok {
+			continue
+		}
+		switch filterType {
+		case "number":
+			num, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("config.filter %q value %q is not a number", id, v)
+			}
+			values[i] = num
+		case "boolean":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("config.filter %q value %q is not a boolean", id, v)
+			}
+			values[i] = b
+		default:
+			values[i] = v
+		}
+	}
+	return values, nil
+}
+
 func schemaToDataviewLayer(schema map[string]interface{}) api.DataviewLayer {
 	el := api.DataviewLayer{
 		ID: schema["id"].(string),
@@ -422,9 +568,12 @@ func schemaToDataviewLayer(schema map[string]interface{}) api.DataviewLayer {
 func resourceDataviewDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
+	if diags := checkDeletionProtection(d, "dataview"); diags.HasError() {
+		return diags
+	}
 	dataviewId := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	_, err := c.DeleteDataview(dataviewId)
 	if err != nil {
 		return diag.FromErr(err)
@@ -433,7 +582,7 @@ func resourceDataviewDelete(ctx context.Context, d *schema.ResourceData, m inter
 	return diags
 }
 
-func flattenDataviewConfiguration(config api.DataviewConfiguration) interface{} {
+func flattenDataviewConfiguration(config api.DataviewConfiguration, typedFilters, typedClusterMaxZoomLevels bool) interface{} {
 	a := make(map[string]interface{})
 
 	a["type"] = config.Type
@@ -447,19 +596,27 @@ func flattenDataviewConfiguration(config api.DataviewConfiguration) interface{}
 	a["intervals"] = config.Intervals
 
 	if config.ClusterMaxZoomLevels != nil {
-		jsonStr, err := json.Marshal(config.ClusterMaxZoomLevels)
-		if err != nil {
-			return diag.FromErr(err)
+		if levels, ok := flattenDataviewClusterMaxZoomLevels(*config.ClusterMaxZoomLevels); typedClusterMaxZoomLevels && ok {
+			a["cluster_max_zoom_level"] = levels
+		} else {
+			jsonStr, err := json.Marshal(config.ClusterMaxZoomLevels)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			a["cluster_max_zoom_levels"] = string(jsonStr)
 		}
-		a["cluster_max_zoom_levels"] = string(jsonStr)
 	}
 
 	if config.Filters != nil {
-		jsonStr, err := json.Marshal(config.Filters)
-		if err != nil {
-			return diag.FromErr(err)
+		if filters, ok := flattenDataviewFilters(*config.Filters); typedFilters && ok {
+			a["filter"] = filters
+		} else {
+			jsonStr, err := json.Marshal(config.Filters)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			a["filters"] = string(jsonStr)
 		}
-		a["filters"] = string(jsonStr)
 	}
 
 	if config.Layers != nil {
@@ -482,3 +639,218 @@ func flattenDataviewLayer(layers []api.DataviewLayer) interface{} {
 
 	return a
 }
+
+// flattenDataviewClusterMaxZoomLevels returns false when a level can not be
+// represented by a cluster_max_zoom_level block, so the caller can fall back
+// to the raw JSON attribute.
+func flattenDataviewClusterMaxZoomLevels(levels map[string]interface{}) ([]interface{}, bool) {
+	list := make([]interface{}, 0, len(levels))
+	for name, zoom := range levels {
+		z, ok := zoom.(float64)
+		if !ok || z != float64(int(z)) {
+			return nil, false
+		}
+		list = append(list, map[string]interface{}{
+			"name": name,
+			"zoom": int(z),
+		})
+	}
+	return list, true
+}
+
+// flattenDataviewFilters returns false when a filter value is not a list of
+// strings, numbers or booleans of a single type, so the caller can fall back
+// to the raw JSON attribute.
+func flattenDataviewFilters(filters map[string]interface{}) ([]interface{}, bool) {
+	list := make([]interface{}, 0, len(filters))
+	for id, value := range filters {
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil, false
+		}
+		filterType := "string"
+		strs := make([]interface{}, len(values))
+		for i, v := range values {
+			var valueType string
+			switch v := v.(type) {
+			case string:
+				valueType, strs[i] = "string", v
+			case float64:
+				valueType, strs[i] = "number", strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				valueType, strs[i] = "boolean", strconv.FormatBool(v)
+			default:
+				return nil, false
+			}
+			if i > 0 && valueType != filterType {
+				return nil, false
+			}
+			filterType = valueType
+		}
+		list = append(list, map[string]interface{}{
+			"id":     id,
+			"values": strs,
+			"type":   filterType,
+		})
+	}
+	return list, true
+}
+
+func dataviewConfigHasBlock(d *schema.ResourceData, key string) bool {
+	set, ok := d.Get(fmt.Sprintf("config.0.%s", key)).(*schema.Set)
+	return ok && set.Len() > 0
+}
+
+func resourceDataviewCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config := m.(*Config)
+	if err := validateEnumValues(config, dataviewEnumValues(d)); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("config") {
+		return nil
+	}
+	filters := d.Get("config.0.filter").(*schema.Set).List()
+	checkFilters := len(filters) > 0
+	if !config.ValidateReferences && !checkFilters {
+		return nil
+	}
+
+	datasets, diags := checkDatasetReferences(config, dataviewDatasetReferences(d.Get("config").([]interface{})))
+	if config.ValidateReferences {
+		if err := diagsToPlanError(diags); err != nil {
+			return err
+		}
+	}
+	if checkFilters {
+		return validateDataviewFilters(filters, datasets)
+	}
+	return nil
+}
+
+// validateDataviewFilters checks the values of the typed filter blocks against
+// their type and the filters declared by the datasets the dataview references.
+func validateDataviewFilters(filters []interface{}, datasets map[string]*api.Dataset) error {
+	for _, f := range filters {
+		if _, err := dataviewFilterValues(f.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+	if len(datasets) == 0 {
+		return nil
+	}
+
+	definitions := map[string][]api.FilterConfig{}
+	resolved := make([]string, 0, len(datasets))
+	for id, dataset := range datasets {
+		resolved = append(resolved, id)
+		if dataset.Filters == nil {
+			continue
+		}
+		for _, f := range datasetFilterConfigs(*dataset.Filters) {
+			definitions[f.ID] = append(definitions[f.ID], f)
+		}
+	}
+	sort.Strings(resolved)
+
+	for _, f := range filters {
+		filter := f.(map[string]interface{})
+		id := filter["id"].(string)
+		values := []string{}
+		for _, v := range filter["values"].([]interface{}) {
+			if v, ok := v.(string); ok {
+				values = append(values, v)
+			}
+		}
+		defs, ok := definitions[id]
+		if !ok {
+			available := make([]string, 0, len(definitions))
+			for k := range definitions {
+				available = append(available, k)
+			}
+			sort.Strings(available)
+			return fmt.Errorf("config.filter %q is not defined by datasets %s, available filters: %s", id, strings.Join(resolved, ", "), strings.Join(available, ", "))
+		}
+		var err error
+		for _, def := range defs {
+			if err = validateDataviewFilterValues(id, values, def); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateDataviewFilterValues(id string, values []string, def api.FilterConfig) error {
+	if def.SingleSelection && len(values) > 1 {
+		return fmt.Errorf("config.filter %q only allows a single value, got %d", id, len(values))
+	}
+	for _, v := range values {
+		if len(def.Enum) > 0 && !utils.ContainsString(def.Enum, v) {
+			return fmt.Errorf("config.filter %q value %q is not one of %s", id, v, strings.Join(def.Enum, ", "))
+		}
+		if def.MaxLength > 0 && len(v) > def.MaxLength {
+			return fmt.Errorf("config.filter %q value %q is longer than %d characters", id, v, def.MaxLength)
+		}
+		if def.MinLength > 0 && len(v) < def.MinLength {
+			return fmt.Errorf("config.filter %q value %q is shorter than %d characters", id, v, def.MinLength)
+		}
+		if def.Type == "number" {
+			num, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("config.filter %q value %q is not a number", id, v)
+			}
+			if def.Min != nil && num < *def.Min {
+				return fmt.Errorf("config.filter %q value %q is lower than %v", id, v, *def.Min)
+			}
+			if def.Max != nil && num > *def.Max {
+				return fmt.Errorf("config.filter %q value %q is greater than %v", id, v, *def.Max)
+			}
+		}
+	}
+	return nil
+}
+
+func dataviewEnumValues(d resourceGetter) []enumValue {
+	values := []enumValue{
+		{Path: "category", Enum: ENUM_DATAVIEW_CATEGORIES, Value: d.Get("category").(string)},
+		{Path: "app", Enum: ENUM_DATAVIEW_APPS, Value: d.Get("app").(string)},
+	}
+	if config := d.Get("config").([]interface{}); len(config) > 0 && config[0] != nil {
+		values = append(values, enumValue{
+			Path:  "config.type",
+			Enum:  ENUM_DATAVIEW_CONFIG_TYPES,
+			Value: config[0].(map[string]interface{})["type"].(string),
+		})
+	}
+	return values
+}
+
+func dataviewDatasetReferences(config []interface{}) []reference {
+	refs := []reference{}
+	if len(config) == 0 || config[0] == nil {
+		return refs
+	}
+	mp := config[0].(map[string]interface{})
+	if datasets, ok := mp["datasets"].([]interface{}); ok {
+		for i, id := range datasets {
+			if id != nil && id.(string) != "" {
+				refs = append(refs, reference{Path: fmt.Sprintf("config.datasets.%d", i), ID: id.(string)})
+			}
+		}
+	}
+	if layers, ok := mp["layers"].([]interface{}); ok {
+		for i, l := range layers {
+			if l == nil {
+				continue
+			}
+			if id, ok := l.(map[string]interface{})["dataset"].(string); ok && id != "" {
+				refs = append(refs, reference{Path: fmt.Sprintf("config.layers.%d.dataset", i), ID: id})
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,145 @@
+package gfw
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccDataview_basic(t *testing.T) {
+	s := testAccServer(t)
+	if _, err := s.Put(fake.Datasets, map[string]interface{}{"id": "test-mpa:v1", "type": "context-layer:v1"}); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Dataviews, "gfw_dataview"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_dataview.test",
+			Collection: fake.Dataviews,
+			Create: resource.TestStep{
+				Config: testAccDataviewConfig("Protected areas", "#ff0000"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataview.test", "slug", "test-mpa"),
+					resource.TestCheckResourceAttr("gfw_dataview.test", "name", "Protected areas"),
+					resource.TestCheckResourceAttr("gfw_dataview.test", "config.0.type", "CONTEXT"),
+					resource.TestCheckResourceAttr("gfw_dataview.test", "config.0.color", "#ff0000"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccDataviewConfig("Marine protected areas", "#00ff00"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_dataview.test", "name", "Marine protected areas"),
+					resource.TestCheckResourceAttr("gfw_dataview.test", "config.0.color", "#00ff00"),
+				),
+			},
+		}),
+	})
+}
+
+func testAccDataviewConfig(name string, color string) string {
+	return fmt.Sprintf(`
+resource "gfw_dataview" "test" {
+  slug        = "test-mpa"
+  name        = %q
+  description = "Marine protected areas"
+  category    = "context"
+  app         = "fishing-map"
+
+  config {
+    type     = "CONTEXT"
+    color    = %q
+    datasets = ["test-mpa:v1"]
+  }
+
+  datasets_config = [jsonencode({
+    datasetId = "test-mpa:v1"
+    endpoint  = "context-tiles"
+    params    = []
+  })]
+}
+`, name, color)
+}
+
+// Typed filter values are sent as numbers and booleans and read back without
+// a diff.
+func TestAccDataview_filters(t *testing.T) {
+	s := testAccServer(t)
+	if _, err := s.Put(fake.Datasets, map[string]interface{}{
+		"id":   "test-fishing:v1",
+		"type": "4wings:v1",
+		"filters": map[string]interface{}{"fourwings": []interface{}{
+			map[string]interface{}{"id": "distance_from_port_km", "type": "number", "min": 0, "max": 100},
+			map[string]interface{}{"id": "fishing", "type": "boolean"},
+			map[string]interface{}{"id": "flag", "type": "string"},
+		}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Dataviews, "gfw_dataview"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataviewFiltersConfig(`["12.5", "50"]`, "number"),
+				Check: func(state *terraform.State) error {
+					dataview, ok := s.Get(fake.Dataviews, state.RootModule().Resources["gfw_dataview.filters"].Primary.ID)
+					if !ok {
+						return fmt.Errorf("dataview not found")
+					}
+					filters := dataview["config"].(map[string]interface{})["filters"].(map[string]interface{})
+					expected := map[string]interface{}{
+						"distance_from_port_km": []interface{}{12.5, float64(50)},
+						"fishing":               []interface{}{true},
+						"flag":                  []interface{}{"ESP"},
+					}
+					if !reflect.DeepEqual(filters, expected) {
+						return fmt.Errorf("expected filters %v, got %v", expected, filters)
+					}
+					return nil
+				},
+			},
+			{
+				Config:      testAccDataviewFiltersConfig(`["far"]`, "number"),
+				ExpectError: regexp.MustCompile(`value "far" is not a number`),
+			},
+		},
+	})
+}
+
+func testAccDataviewFiltersConfig(distance string, distanceType string) string {
+	return fmt.Sprintf(`
+resource "gfw_dataview" "filters" {
+  slug        = "test-fishing"
+  name        = "Fishing effort"
+  description = "Fishing effort near ports"
+  category    = "activity"
+  app         = "fishing-map"
+
+  config {
+    type     = "HEATMAP_ANIMATED"
+    datasets = ["test-fishing:v1"]
+
+    filter {
+      id     = "distance_from_port_km"
+      type   = %q
+      values = %s
+    }
+    filter {
+      id     = "fishing"
+      type   = "boolean"
+      values = ["true"]
+    }
+    filter {
+      id     = "flag"
+      values = ["ESP"]
+    }
+  }
+}
+`, distanceType, distance)
+}
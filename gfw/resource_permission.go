@@ -16,20 +16,26 @@ func resourcePermission() *schema.Resource {
 		ReadContext:   resourcePermissionRead,
 		UpdateContext: resourcePermissionUpdate,
 		DeleteContext: resourcePermissionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"resource": &schema.Schema{
 				Type:     schema.TypeList,
 				MaxItems: 1,
 				Required: true,
+				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": &schema.Schema{
 							Type:     schema.TypeInt,
 							Required: true,
+							ForceNew: true,
 						},
 						"type": &schema.Schema{
 							Type:     schema.TypeString,
@@ -54,11 +60,13 @@ func resourcePermission() *schema.Resource {
 				Type:     schema.TypeList,
 				MaxItems: 1,
 				Required: true,
+				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"id": &schema.Schema{
 							Type:     schema.TypeInt,
 							Required: true,
+							ForceNew: true,
 						},
 						"name": &schema.Schema{
 							Type:     schema.TypeString,
@@ -79,6 +87,7 @@ func resourcePermission() *schema.Resource {
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"created_at": &schema.Schema{
 				Type:     schema.TypeString,
@@ -98,7 +107,7 @@ func resourcePermission() *schema.Resource {
 
 func resourcePermissionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 	name := d.Get("name").(string)
 	action := d.Get("action").([]interface{})[0].(map[string]interface{})
@@ -122,9 +131,13 @@ func resourcePermissionCreate(ctx context.Context, d *schema.ResourceData, m int
 func resourcePermissionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	permissionID := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	permission, err := c.GetPermission(permissionID)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 
@@ -153,7 +166,7 @@ func resourcePermissionDelete(ctx context.Context, d *schema.ResourceData, m int
 	var diags diag.Diagnostics
 	permissionID := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	_, err := c.DeletePermission(permissionID)
 	if err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,70 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPermission_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Permissions, "gfw_permission"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_permission.test",
+			Collection: fake.Permissions,
+			Create: resource.TestStep{
+				Config: testAccPermissionConfig("read", "Read datasets"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_permission.test", "name", "read-datasets"),
+					resource.TestCheckResourceAttrPair("gfw_permission.test", "action.0.id", "gfw_action.read", "id"),
+					resource.TestCheckResourceAttr("gfw_permission.test", "action.0.name", "read"),
+					resource.TestCheckResourceAttrPair("gfw_permission.test", "resource.0.id", "gfw_resource.datasets", "id"),
+					resource.TestCheckResourceAttr("gfw_permission.test", "resource.0.type", "dataset"),
+				),
+			},
+			// Permissions cannot be updated, the change replaces it.
+			Update: resource.TestStep{
+				Config: testAccPermissionConfig("write", "Write datasets"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gfw_permission.test", "action.0.id", "gfw_action.write", "id"),
+					resource.TestCheckResourceAttr("gfw_permission.test", "description", "Write datasets"),
+				),
+			},
+		}),
+	})
+}
+
+func testAccPermissionConfig(action string, description string) string {
+	return fmt.Sprintf(`
+resource "gfw_action" "read" {
+  name        = "read"
+  description = "Read"
+}
+
+resource "gfw_action" "write" {
+  name        = "write"
+  description = "Write"
+}
+
+resource "gfw_resource" "datasets" {
+  type        = "dataset"
+  value       = "*"
+  description = "Every dataset"
+}
+
+resource "gfw_permission" "test" {
+  name        = "read-datasets"
+  description = %q
+  action {
+    id = gfw_action.%s.id
+  }
+  resource {
+    id = gfw_resource.datasets.id
+  }
+}
+`, description, action)
+}
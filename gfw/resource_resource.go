@@ -16,18 +16,24 @@ func resourceResource() *schema.Resource {
 		ReadContext:   resourceResourceRead,
 		UpdateContext: resourceResourceUpdate,
 		DeleteContext: resourceResourceDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 		Schema: map[string]*schema.Schema{
 			"type": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"value": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"created_at": &schema.Schema{
 				Type:     schema.TypeString,
@@ -47,7 +53,7 @@ func resourceResource() *schema.Resource {
 
 func resourceResourceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	rType := d.Get("type").(string)
@@ -69,9 +75,13 @@ func resourceResourceCreate(ctx context.Context, d *schema.ResourceData, m inter
 func resourceResourceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	resourceId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	resource, err := c.GetResource(resourceId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 
@@ -92,7 +102,7 @@ func resourceResourceDelete(ctx context.Context, d *schema.ResourceData, m inter
 	var diags diag.Diagnostics
 	resourceId := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	_, err := c.DeleteResource(resourceId)
 	if err != nil {
 		return diag.FromErr(err)
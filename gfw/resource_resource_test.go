@@ -0,0 +1,44 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccResource_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Resources, "gfw_resource"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_resource.test",
+			Collection: fake.Resources,
+			Create: resource.TestStep{
+				Config: testAccResourceConfig("dataset", "public-*", "Public datasets"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_resource.test", "type", "dataset"),
+					resource.TestCheckResourceAttr("gfw_resource.test", "value", "public-*"),
+					resource.TestCheckResourceAttrSet("gfw_resource.test", "created_at"),
+				),
+			},
+			// Resources cannot be updated, the change replaces it.
+			Update: resource.TestStep{
+				Config: testAccResourceConfig("dataset", "private-*", "Private datasets"),
+				Check:  resource.TestCheckResourceAttr("gfw_resource.test", "value", "private-*"),
+			},
+		}),
+	})
+}
+
+func testAccResourceConfig(rType string, value string, description string) string {
+	return fmt.Sprintf(`
+resource "gfw_resource" "test" {
+  type        = %q
+  value       = %q
+  description = %q
+}
+`, rType, value, description)
+}
@@ -16,14 +16,19 @@ func resourceRole() *schema.Resource {
 		ReadContext:   resourceRoleRead,
 		UpdateContext: resourceRoleUpdate,
 		DeleteContext: resourceRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"description": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"created_at": &schema.Schema{
 				Type:     schema.TypeString,
@@ -43,7 +48,7 @@ func resourceRole() *schema.Resource {
 
 func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	name := d.Get("name").(string)
@@ -64,9 +69,13 @@ func resourceRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 	actionId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	action, err := c.GetRole(actionId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 
@@ -86,7 +95,7 @@ func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, m interface
 	var diags diag.Diagnostics
 	actionId := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	_, err := c.DeleteRole(actionId)
 	if err != nil {
 		return diag.FromErr(err)
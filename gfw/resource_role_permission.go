@@ -17,10 +17,14 @@ func resourceRolePermissions() *schema.Resource {
 		ReadContext:   resourceRolePermissionsRead,
 		UpdateContext: resourceRolePermissionsUpdate,
 		DeleteContext: resourceRolePermissionsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 		Schema: map[string]*schema.Schema{
 			"role": &schema.Schema{
 				Type:     schema.TypeInt,
 				Required: true,
+				ForceNew: true,
 			},
 			"permissions": &schema.Schema{
 				Type:     schema.TypeSet,
@@ -42,7 +46,7 @@ func resourceRolePermissions() *schema.Resource {
 
 func resourceRolePermissionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	roleId := d.Get("role").(int)
@@ -64,9 +68,13 @@ func resourceRolePermissionsRead(ctx context.Context, d *schema.ResourceData, m
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 	roleId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	role, err := c.GetRole(roleId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 
@@ -85,7 +93,7 @@ func resourceRolePermissionsDelete(ctx context.Context, d *schema.ResourceData,
 	var diags diag.Diagnostics
 	roleId := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	err := c.DeleteRolePermissions(roleId)
 	if err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,93 @@
+package gfw
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccRolePermissions_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Roles, "gfw_role_permissions"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name: "gfw_role_permissions.test",
+			Create: resource.TestStep{
+				Config: testAccRolePermissionsConfig("gfw_permission.read.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gfw_role_permissions.test", "role", "gfw_role.test", "id"),
+					resource.TestCheckResourceAttr("gfw_role_permissions.test", "permissions.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("gfw_role_permissions.test", "permissions.*", "gfw_permission.read", "id"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccRolePermissionsConfig("gfw_permission.read.id", "gfw_permission.write.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_role_permissions.test", "permissions.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair("gfw_role_permissions.test", "permissions.*", "gfw_permission.write", "id"),
+				),
+			},
+			// Permissions removed outside of Terraform are added back.
+			Disappears: func(state *terraform.State) error {
+				return s.APIClient().DeleteRolePermissions(state.RootModule().Resources["gfw_role.test"].Primary.ID)
+			},
+		}),
+	})
+}
+
+func testAccRolePermissionsConfig(permissions ...string) string {
+	return fmt.Sprintf(`
+resource "gfw_action" "read" {
+  name        = "read"
+  description = "Read"
+}
+
+resource "gfw_action" "write" {
+  name        = "write"
+  description = "Write"
+}
+
+resource "gfw_resource" "datasets" {
+  type        = "dataset"
+  value       = "*"
+  description = "Every dataset"
+}
+
+resource "gfw_permission" "read" {
+  name        = "read-datasets"
+  description = "Read datasets"
+  action {
+    id = gfw_action.read.id
+  }
+  resource {
+    id = gfw_resource.datasets.id
+  }
+}
+
+resource "gfw_permission" "write" {
+  name        = "write-datasets"
+  description = "Write datasets"
+  action {
+    id = gfw_action.write.id
+  }
+  resource {
+    id = gfw_resource.datasets.id
+  }
+}
+
+resource "gfw_role" "test" {
+  name        = "editor"
+  description = "Edits datasets"
+}
+
+resource "gfw_role_permissions" "test" {
+  role        = gfw_role.test.id
+  permissions = [%s]
+}
+`, strings.Join(permissions, ", "))
+}
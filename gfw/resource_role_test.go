@@ -0,0 +1,43 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRole_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Roles, "gfw_role"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_role.test",
+			Collection: fake.Roles,
+			Create: resource.TestStep{
+				Config: testAccRoleConfig("reader", "Reads datasets"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_role.test", "name", "reader"),
+					resource.TestCheckResourceAttr("gfw_role.test", "description", "Reads datasets"),
+					resource.TestCheckResourceAttrSet("gfw_role.test", "created_at"),
+				),
+			},
+			// Roles cannot be updated, the change replaces it.
+			Update: resource.TestStep{
+				Config: testAccRoleConfig("reader", "Reads every dataset"),
+				Check:  resource.TestCheckResourceAttr("gfw_role.test", "description", "Reads every dataset"),
+			},
+		}),
+	})
+}
+
+func testAccRoleConfig(name string, description string) string {
+	return fmt.Sprintf(`
+resource "gfw_role" "test" {
+  name        = %q
+  description = %q
+}
+`, name, description)
+}
@@ -16,6 +16,9 @@ func resourceUserGroup() *schema.Resource {
 		ReadContext:   resourceUserGroupRead,
 		UpdateContext: resourceUserGroupUpdate,
 		DeleteContext: resourceUserGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -48,7 +51,7 @@ func resourceUserGroup() *schema.Resource {
 
 func resourceUserGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	name := d.Get("name").(string)
@@ -71,9 +74,13 @@ func resourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interf
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 	userGroupId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	userGroup, err := c.GetUserGroup(userGroupId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 
@@ -91,7 +98,7 @@ func resourceUserGroupUpdate(ctx context.Context, d *schema.ResourceData, m inte
 	defaultV := d.Get("default").(bool)
 
 	userGroupID := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	err := c.UpdateUserGroup(userGroupID, api.CreateUserGroup{
 		Name:        name,
 		Description: description,
@@ -108,7 +115,7 @@ func resourceUserGroupDelete(ctx context.Context, d *schema.ResourceData, m inte
 	var diags diag.Diagnostics
 	userGroupId := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	_, err := c.DeleteUserGroup(userGroupId)
 	if err != nil {
 		return diag.FromErr(err)
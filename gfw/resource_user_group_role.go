@@ -17,10 +17,14 @@ func resourceUserGroupRole() *schema.Resource {
 		ReadContext:   resourceUserGroupRoleRead,
 		UpdateContext: resourceUserGroupRoleUpdate,
 		DeleteContext: resourceUserGroupRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
 		Schema: map[string]*schema.Schema{
 			"user_group": &schema.Schema{
 				Type:     schema.TypeInt,
 				Required: true,
+				ForceNew: true,
 			},
 			"roles": &schema.Schema{
 				Type:     schema.TypeSet,
@@ -42,7 +46,7 @@ func resourceUserGroupRole() *schema.Resource {
 
 func resourceUserGroupRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	var diags diag.Diagnostics
 
 	userGroupID := d.Get("user_group").(int)
@@ -64,9 +68,13 @@ func resourceUserGroupRoleRead(ctx context.Context, d *schema.ResourceData, m in
 	// Warning or errors can be collected in a slice type
 	var diags diag.Diagnostics
 	userGroupId := d.Id()
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	userGroup, err := c.GetUserGroup(userGroupId)
 	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
 		return diag.FromErr(err)
 	}
 
@@ -85,7 +93,7 @@ func resourceUserGroupRoleDelete(ctx context.Context, d *schema.ResourceData, m
 	var diags diag.Diagnostics
 	roleId := d.Id()
 
-	c := m.(*api.GFWClient)
+	c := m.(*Config).Client
 	err := c.DeleteUserGroupRole(roleId)
 	if err != nil {
 		return diag.FromErr(err)
@@ -0,0 +1,65 @@
+package gfw
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccUserGroupRole_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.UserGroups, "gfw_user_group_role"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name: "gfw_user_group_role.test",
+			Create: resource.TestStep{
+				Config: testAccUserGroupRoleConfig("gfw_role.reader.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("gfw_user_group_role.test", "user_group", "gfw_user_group.test", "id"),
+					resource.TestCheckResourceAttr("gfw_user_group_role.test", "roles.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair("gfw_user_group_role.test", "roles.*", "gfw_role.reader", "id"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccUserGroupRoleConfig("gfw_role.reader.id", "gfw_role.editor.id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_user_group_role.test", "roles.#", "2"),
+					resource.TestCheckTypeSetElemAttrPair("gfw_user_group_role.test", "roles.*", "gfw_role.editor", "id"),
+				),
+			},
+			// Roles removed outside of Terraform are added back.
+			Disappears: func(state *terraform.State) error {
+				return s.APIClient().DeleteUserGroupRole(state.RootModule().Resources["gfw_user_group.test"].Primary.ID)
+			},
+		}),
+	})
+}
+
+func testAccUserGroupRoleConfig(roles ...string) string {
+	return fmt.Sprintf(`
+resource "gfw_role" "reader" {
+  name        = "reader"
+  description = "Reads datasets"
+}
+
+resource "gfw_role" "editor" {
+  name        = "editor"
+  description = "Edits datasets"
+}
+
+resource "gfw_user_group" "test" {
+  name        = "Researchers"
+  description = "External researchers"
+}
+
+resource "gfw_user_group_role" "test" {
+  user_group = gfw_user_group.test.id
+  roles      = [%s]
+}
+`, strings.Join(roles, ", "))
+}
@@ -0,0 +1,46 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUserGroup_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.UserGroups, "gfw_user_group"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_user_group.test",
+			Collection: fake.UserGroups,
+			Create: resource.TestStep{
+				Config: testAccUserGroupConfig("Researchers", "External researchers", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_user_group.test", "name", "Researchers"),
+					resource.TestCheckResourceAttr("gfw_user_group.test", "default", "false"),
+					resource.TestCheckResourceAttrSet("gfw_user_group.test", "created_at"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccUserGroupConfig("Researchers", "Every researcher", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_user_group.test", "description", "Every researcher"),
+					resource.TestCheckResourceAttr("gfw_user_group.test", "default", "true"),
+				),
+			},
+		}),
+	})
+}
+
+func testAccUserGroupConfig(name string, description string, isDefault bool) string {
+	return fmt.Sprintf(`
+resource "gfw_user_group" "test" {
+  name        = %q
+  description = %q
+  default     = %t
+}
+`, name, description, isDefault)
+}
@@ -3,12 +3,29 @@ package gfw
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/utils"
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
@@ -17,282 +34,848 @@ var WORKSPACE_CATEGORIES []string = []string{"marine-reserves",
 	"fishing-activity",
 	"country-portals"}
 
-func resourceWorkspace() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceWorkspaceCreate,
-		ReadContext:   resourceWorkspaceRead,
-		UpdateContext: resourceWorkspaceUpdate,
-		DeleteContext: resourceWorkspaceDelete,
-		Schema: map[string]*schema.Schema{
-			"workspace_id": {
-				Type:     schema.TypeString,
+var WORKSPACE_VIEW_ACCESS []string = []string{"public", "private", "password"}
+
+var WORKSPACE_EDIT_ACCESS []string = []string{"private", "password"}
+
+// AOI_DATASET_TYPES are the dataset types whose areas can be used as AOI.
+var AOI_DATASET_TYPES []string = []string{
+	"context-layer:v1",
+	"user-context-layer:v1",
+}
+
+var WORKSPACE_STATE_CONFIG_ATTRIBUTE_TYPES = map[string]attr.Type{
+	"timebar_visualisation": types.StringType,
+	"timebar_graph":         types.StringType,
+	"bivariate_dataviews":   types.ListType{ElemType: types.StringType},
+	"sidebar_open":          types.BoolType,
+	"report_dataset_id":     types.StringType,
+	"report_area_id":        types.StringType,
+}
+
+var WORKSPACE_VIEWPORT_ATTRIBUTE_TYPES = map[string]attr.Type{
+	"zoom":      types.Float64Type,
+	"latitude":  types.Float64Type,
+	"longitude": types.Float64Type,
+}
+
+var WORKSPACE_DATAVIEW_INSTANCE_ATTRIBUTE_TYPES = map[string]attr.Type{
+	"id":              types.StringType,
+	"category":        types.StringType,
+	"config":          types.StringType,
+	"dataview_id":     types.StringType,
+	"datasets_config": types.ListType{ElemType: types.StringType},
+}
+
+// workspaceResource is the first resource served by the framework provider.
+// The schema is the one of the SDKv2 implementation, whose states are
+// upgraded from schema version 0.
+type workspaceResource struct {
+	config *Config
+}
+
+var (
+	_ resource.ResourceWithConfigure    = &workspaceResource{}
+	_ resource.ResourceWithImportState  = &workspaceResource{}
+	_ resource.ResourceWithModifyPlan   = &workspaceResource{}
+	_ resource.ResourceWithUpgradeState = &workspaceResource{}
+)
+
+type workspaceModel struct {
+	ID                              types.String   `tfsdk:"id"`
+	DeletionProtection              types.Bool     `tfsdk:"deletion_protection"`
+	WorkspaceID                     types.String   `tfsdk:"workspace_id"`
+	Name                            types.String   `tfsdk:"name"`
+	Description                     types.String   `tfsdk:"description"`
+	Category                        types.String   `tfsdk:"category"`
+	App                             types.String   `tfsdk:"app"`
+	Public                          types.Bool     `tfsdk:"public"`
+	State                           types.String   `tfsdk:"state"`
+	ViewAccess                      types.String   `tfsdk:"view_access"`
+	EditAccess                      types.String   `tfsdk:"edit_access"`
+	Password                        types.String   `tfsdk:"password"`
+	PasswordVersion                 types.Int64    `tfsdk:"password_version"`
+	EditorUserGroups                types.Set      `tfsdk:"editor_user_groups"`
+	StateConfig                     types.List     `tfsdk:"state_config"`
+	StartAt                         types.String   `tfsdk:"start_at"`
+	EndAt                           types.String   `tfsdk:"end_at"`
+	Aoi                             types.String   `tfsdk:"aoi"`
+	Viewport                        types.List     `tfsdk:"viewport"`
+	Dataviews                       types.List     `tfsdk:"dataviews"`
+	DataviewInstances               types.List     `tfsdk:"dataview_instances"`
+	IgnoreExternalDataviewInstances types.Bool     `tfsdk:"ignore_external_dataview_instances"`
+	CreatedAt                       types.String   `tfsdk:"created_at"`
+	Timeouts                        timeouts.Value `tfsdk:"timeouts"`
+}
+
+func newWorkspaceResource() resource.Resource {
+	return &workspaceResource{}
+}
+
+func (r *workspaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace"
+}
+
+func (r *workspaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if config, ok := req.ProviderData.(*Config); ok {
+		r.config = config
+	}
+}
+
+func (r *workspaceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = workspaceSchema(ctx)
+}
+
+func workspaceSchema(ctx context.Context) resourceschema.Schema {
+	return resourceschema.Schema{
+		Version: 1,
+		Attributes: map[string]resourceschema.Attribute{
+			"id": resourceschema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"deletion_protection": deletionProtectionAttribute(false),
+			"workspace_id": resourceschema.StringAttribute{
 				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"name": {
-				Type:     schema.TypeString,
+			"name": resourceschema.StringAttribute{
 				Required: true,
 			},
-			"description": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
+			"description": resourceschema.StringAttribute{
+				Required:   true,
+				Validators: []validator.String{stringvalidator.LengthAtLeast(1)},
 			},
-			"category": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice(WORKSPACE_CATEGORIES, false),
+			"category": resourceschema.StringAttribute{
+				Optional: true,
 			},
-			"app": {
-				Type:     schema.TypeString,
+			"app": resourceschema.StringAttribute{
 				Required: true,
 			},
-			"public": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+			"public": resourceschema.BoolAttribute{
+				Optional:      true,
+				Computed:      true,
+				Default:       booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.RequiresReplace()},
 			},
-			"state": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringIsJSON,
+			"state": resourceschema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{sdkStringValidator{validation.StringIsJSON}},
 			},
-			"start_at": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: utils.IsISOTime,
+			"view_access": resourceschema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Validators:    []validator.String{stringvalidator.OneOf(WORKSPACE_VIEW_ACCESS...)},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"end_at": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: utils.IsISOTime,
+			"edit_access": resourceschema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Validators:    []validator.String{stringvalidator.OneOf(WORKSPACE_EDIT_ACCESS...)},
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 			},
-			"aoi": {
-				Type:     schema.TypeString,
-				Optional: true,
+			// The password can not be read back, it is only sent on create
+			// and when password_version changes.
+			"password": resourceschema.StringAttribute{
+				Optional:    true,
+				WriteOnly:   true,
+				Description: "Password of the workspace when `view_access` or `edit_access` is `password`. It is write-only, never stored in the plan or the state, and requires Terraform 1.11 or later. It is sent on create and when `password_version` changes.",
 			},
-			"viewport": {
-				Type:     schema.TypeList,
+			"password_version": resourceschema.Int64Attribute{
+				Optional:    true,
+				Description: "Version of `password`, change it to send the password again.",
+			},
+			"editor_user_groups": resourceschema.SetAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+			},
+			"start_at": resourceschema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{sdkStringValidator{utils.IsISOTime}},
+			},
+			"end_at": resourceschema.StringAttribute{
+				Optional:   true,
+				Validators: []validator.String{sdkStringValidator{utils.IsISOTime}},
+			},
+			"aoi": resourceschema.StringAttribute{
 				Optional: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"zoom": {
-							Type:     schema.TypeFloat,
-							Required: true,
+			},
+			"dataviews": resourceschema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
+			},
+			// Dataview instances not listed in dataview_instances, such as
+			// the ones managed by gfw_workspace_dataview_instance, are left
+			// untouched and not read into the state.
+			"ignore_external_dataview_instances": resourceschema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Keep the dataview instances not listed in `dataview_instances`, such as the ones managed by `gfw_workspace_dataview_instance`.",
+			},
+			"created_at": resourceschema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+		},
+		Blocks: map[string]resourceschema.Block{
+			// Typed view of the commonly used state keys, merged with the
+			// raw state JSON on write and split back out on read. The keys
+			// of the attributes removed from the block are removed from the
+			// state.
+			"state_config": resourceschema.ListNestedBlock{
+				Validators: []validator.List{listvalidator.SizeAtMost(1)},
+				NestedObject: resourceschema.NestedBlockObject{
+					Attributes: map[string]resourceschema.Attribute{
+						"timebar_visualisation": resourceschema.StringAttribute{
+							Optional: true,
 						},
-						"latitude": {
-							Type:     schema.TypeFloat,
-							Required: true,
+						"timebar_graph": resourceschema.StringAttribute{
+							Optional: true,
 						},
-						"longitude": {
-							Type:     schema.TypeFloat,
-							Required: true,
+						"bivariate_dataviews": resourceschema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators:  []validator.List{listvalidator.SizeAtMost(2)},
+						},
+						"sidebar_open": resourceschema.BoolAttribute{
+							Optional: true,
+						},
+						"report_dataset_id": resourceschema.StringAttribute{
+							Optional: true,
+						},
+						"report_area_id": resourceschema.StringAttribute{
+							Optional: true,
 						},
 					},
 				},
 			},
-			"dataviews": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeInt,
+			"viewport": resourceschema.ListNestedBlock{
+				Validators: []validator.List{listvalidator.SizeAtMost(1)},
+				NestedObject: resourceschema.NestedBlockObject{
+					Attributes: map[string]resourceschema.Attribute{
+						"zoom": resourceschema.Float64Attribute{
+							Required: true,
+						},
+						"latitude": resourceschema.Float64Attribute{
+							Required:   true,
+							Validators: []validator.Float64{float64validator.Between(-90, 90)},
+						},
+						// Longitudes are sent normalised to [-180, 180], the
+						// configured value is kept when equivalent.
+						"longitude": resourceschema.Float64Attribute{
+							Required: true,
+						},
+					},
 				},
 			},
-
-			"dataview_instances": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
+			"dataview_instances": resourceschema.ListNestedBlock{
+				NestedObject: resourceschema.NestedBlockObject{
+					Attributes: map[string]resourceschema.Attribute{
+						"id": resourceschema.StringAttribute{
 							Required: true,
 						},
-						"category": {
-							Type:     schema.TypeString,
+						"category": resourceschema.StringAttribute{
 							Optional: true,
 						},
-						"config": {
-							Type:         schema.TypeString,
-							Optional:     true,
-							ValidateFunc: validation.StringIsJSON,
+						"config": resourceschema.StringAttribute{
+							Optional:   true,
+							Validators: []validator.String{sdkStringValidator{validation.StringIsJSON}},
 						},
-						"dataview_id": {
-							Type:     schema.TypeString,
+						"dataview_id": resourceschema.StringAttribute{
 							Required: true,
 						},
-						"datasets_config": {
-							Type:     schema.TypeList,
-							Optional: true,
-							Elem: &schema.Schema{
-								Type:         schema.TypeString,
-								ValidateFunc: validation.StringIsJSON,
-							},
+						"datasets_config": resourceschema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+							Validators:  []validator.List{listvalidator.ValueStringsAre(sdkStringValidator{validation.StringIsJSON})},
 						},
 					},
 				},
 			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
 
-			"created_at": {
-				Type:     schema.TypeString,
-				Computed: true,
-				Optional: true,
+// workspaceModelV0 is the state of the SDKv2 implementation, which stored
+// unset attributes as empty strings and lists.
+type workspaceModelV0 struct {
+	ID                types.String   `tfsdk:"id"`
+	WorkspaceID       types.String   `tfsdk:"workspace_id"`
+	Name              types.String   `tfsdk:"name"`
+	Description       types.String   `tfsdk:"description"`
+	Category          types.String   `tfsdk:"category"`
+	App               types.String   `tfsdk:"app"`
+	Public            types.Bool     `tfsdk:"public"`
+	State             types.String   `tfsdk:"state"`
+	StartAt           types.String   `tfsdk:"start_at"`
+	EndAt             types.String   `tfsdk:"end_at"`
+	Aoi               types.String   `tfsdk:"aoi"`
+	Viewport          types.List     `tfsdk:"viewport"`
+	Dataviews         types.List     `tfsdk:"dataviews"`
+	DataviewInstances types.List     `tfsdk:"dataview_instances"`
+	CreatedAt         types.String   `tfsdk:"created_at"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// workspaceSchemaV0 is the schema of the SDKv2 implementation. It is frozen,
+// changes to the resource go in workspaceSchema.
+func workspaceSchemaV0(ctx context.Context) resourceschema.Schema {
+	return resourceschema.Schema{
+		Attributes: map[string]resourceschema.Attribute{
+			"id":           resourceschema.StringAttribute{Computed: true},
+			"workspace_id": resourceschema.StringAttribute{Optional: true},
+			"name":         resourceschema.StringAttribute{Required: true},
+			"description":  resourceschema.StringAttribute{Required: true},
+			"category":     resourceschema.StringAttribute{Optional: true},
+			"app":          resourceschema.StringAttribute{Required: true},
+			"public":       resourceschema.BoolAttribute{Optional: true},
+			"state":        resourceschema.StringAttribute{Optional: true},
+			"start_at":     resourceschema.StringAttribute{Optional: true},
+			"end_at":       resourceschema.StringAttribute{Optional: true},
+			"aoi":          resourceschema.StringAttribute{Optional: true},
+			"dataviews": resourceschema.ListAttribute{
+				ElementType: types.Int64Type,
+				Optional:    true,
 			},
+			"created_at": resourceschema.StringAttribute{Optional: true, Computed: true},
 		},
-		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(5 * time.Minute),
-			Update: schema.DefaultTimeout(5 * time.Minute),
-			Delete: schema.DefaultTimeout(5 * time.Minute),
-			Read:   schema.DefaultTimeout(5 * time.Minute),
+		Blocks: map[string]resourceschema.Block{
+			"viewport": resourceschema.ListNestedBlock{
+				NestedObject: resourceschema.NestedBlockObject{
+					Attributes: map[string]resourceschema.Attribute{
+						"zoom":      resourceschema.Float64Attribute{Required: true},
+						"latitude":  resourceschema.Float64Attribute{Required: true},
+						"longitude": resourceschema.Float64Attribute{Required: true},
+					},
+				},
+			},
+			"dataview_instances": resourceschema.ListNestedBlock{
+				NestedObject: resourceschema.NestedBlockObject{
+					Attributes: map[string]resourceschema.Attribute{
+						"id":          resourceschema.StringAttribute{Required: true},
+						"category":    resourceschema.StringAttribute{Optional: true},
+						"config":      resourceschema.StringAttribute{Optional: true},
+						"dataview_id": resourceschema.StringAttribute{Required: true},
+						"datasets_config": resourceschema.ListAttribute{
+							ElementType: types.StringType,
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
 		},
 	}
 }
 
-func resourceWorkspaceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	c := m.(*api.GFWClient)
-	var diags diag.Diagnostics
-	id := d.Get("workspace_id").(string)
-	workspace, err := schemaToWorkspace(d)
+// UpgradeState upgrades the states written by the SDKv2 implementation. The
+// attributes added since are set to their defaults, view_access and
+// edit_access are read on the next refresh.
+func (r *workspaceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	prior := workspaceSchemaV0(ctx)
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &prior,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var v0 workspaceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &v0)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				// The SDKv2 implementation never read workspace_id back, it
+				// is empty when the API chose the ID.
+				workspaceID := v0.WorkspaceID
+				if workspaceID.ValueString() == "" {
+					workspaceID = v0.ID
+				}
+				viewport := v0.Viewport
+				if viewport.IsNull() {
+					viewport = types.ListValueMust(types.ObjectType{AttrTypes: WORKSPACE_VIEWPORT_ATTRIBUTE_TYPES}, []attr.Value{})
+				}
+				dataviews := v0.Dataviews
+				if len(dataviews.Elements()) == 0 {
+					dataviews = types.ListNull(types.Int64Type)
+				}
+				instances := make([]attr.Value, 0, len(v0.DataviewInstances.Elements()))
+				for _, object := range listObjects(v0.DataviewInstances) {
+					attributes := object.Attributes()
+					datasetsConfig := priorList(attributes, "datasets_config", types.StringType)
+					if len(datasetsConfig.Elements()) == 0 {
+						datasetsConfig = types.ListNull(types.StringType)
+					}
+					instance, diags := types.ObjectValue(WORKSPACE_DATAVIEW_INSTANCE_ATTRIBUTE_TYPES, map[string]attr.Value{
+						"id":              priorString(attributes, "id"),
+						"category":        optionalString(priorString(attributes, "category").ValueString(), types.StringNull()),
+						"config":          optionalString(priorString(attributes, "config").ValueString(), types.StringNull()),
+						"dataview_id":     priorString(attributes, "dataview_id"),
+						"datasets_config": datasetsConfig,
+					})
+					resp.Diagnostics.Append(diags...)
+					instances = append(instances, instance)
+				}
+				dataviewInstances, diags := types.ListValue(types.ObjectType{AttrTypes: WORKSPACE_DATAVIEW_INSTANCE_ATTRIBUTE_TYPES}, instances)
+				resp.Diagnostics.Append(diags...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				m := workspaceModel{
+					ID:                              v0.ID,
+					DeletionProtection:              types.BoolValue(false),
+					WorkspaceID:                     workspaceID,
+					Name:                            v0.Name,
+					Description:                     v0.Description,
+					Category:                        optionalString(v0.Category.ValueString(), types.StringNull()),
+					App:                             v0.App,
+					Public:                          types.BoolValue(v0.Public.ValueBool()),
+					State:                           optionalString(v0.State.ValueString(), types.StringNull()),
+					ViewAccess:                      types.StringNull(),
+					EditAccess:                      types.StringNull(),
+					Password:                        types.StringNull(),
+					PasswordVersion:                 types.Int64Null(),
+					EditorUserGroups:                types.SetNull(types.Int64Type),
+					StateConfig:                     types.ListValueMust(types.ObjectType{AttrTypes: WORKSPACE_STATE_CONFIG_ATTRIBUTE_TYPES}, []attr.Value{}),
+					StartAt:                         optionalString(v0.StartAt.ValueString(), types.StringNull()),
+					EndAt:                           optionalString(v0.EndAt.ValueString(), types.StringNull()),
+					Aoi:                             optionalString(v0.Aoi.ValueString(), types.StringNull()),
+					Viewport:                        viewport,
+					Dataviews:                       dataviews,
+					DataviewInstances:               dataviewInstances,
+					IgnoreExternalDataviewInstances: types.BoolValue(false),
+					CreatedAt:                       v0.CreatedAt,
+					Timeouts:                        v0.Timeouts,
+				}
+				resp.Diagnostics.Append(resp.State.Set(ctx, m)...)
+			},
+		},
+	}
+}
+
+func (r *workspaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan, config workspaceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, diags := plan.Timeouts.Create(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c := r.config.Client
+	workspace, err := modelToWorkspace(ctx, plan, config, true)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Unable to create workspace", err.Error())
+		return
+	}
+	workspace.ID = plan.WorkspaceID.ValueString()
+	if workspace.ID == "" {
+		workspace.ID = api.WorkspaceID(workspace.Name, plan.Public.ValueBool())
 	}
-	workspace.ID = id
+	// gfw_workspace_dataview_instance resources may write the instances of
+	// the same workspace while it is created.
+	unlock := r.config.workspaceLocks.Lock(workspace.ID)
+	defer unlock()
 	workspaceCreated, err := c.CreateWorkspace(workspace)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Unable to create workspace", err.Error())
+		return
 	}
-	d.SetId(workspaceCreated.ID)
-	resourceWorkspaceRead(ctx, d, m)
-	return diags
+	plan.ID = types.StringValue(workspaceCreated.ID)
+	resp.Diagnostics.Append(frameworkDiagnostics(enumWarnings(r.config, workspaceEnumValues(plan.Category.ValueString())))...)
+	r.read(ctx, &plan, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceWorkspaceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
-	workspaceId := d.Id()
-	c := m.(*api.GFWClient)
-	workspace, err := c.GetWorkspace(workspaceId)
+func (r *workspaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state workspaceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, diags := state.Timeouts.Read(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if !r.read(ctx, &state, &resp.Diagnostics) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// read refreshes m from the API, the values of m being the prior ones. It
+// returns false when the workspace is gone.
+func (r *workspaceResource) read(ctx context.Context, m *workspaceModel, diags *fwdiag.Diagnostics) bool {
+	c := r.config.Client
+	workspace, err := c.GetWorkspace(m.ID.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		if isNotFound(err) {
+			return false
+		}
+		diags.AddError("Unable to read workspace", err.Error())
+		return true
 	}
+	diags.Append(frameworkDiagnostics(unknownFieldsWarning("workspace", m.ID.ValueString(), workspace.UnknownFields))...)
 
-	d.Set("name", workspace.Name)
-	d.Set("description", workspace.Description)
-	d.Set("created_at", workspace.CreatedAt)
-	d.Set("public", workspace.Public)
-	d.Set("app", workspace.App)
-	d.Set("start_at", workspace.StartAt)
-	d.Set("end_at", workspace.EndAt)
-	d.Set("category", workspace.Category)
-	d.Set("aoi", workspace.Aoi)
+	var d fwdiag.Diagnostics
+	m.WorkspaceID = types.StringValue(workspace.ID)
+	m.Name = types.StringValue(workspace.Name)
+	m.Description = types.StringValue(workspace.Description)
+	m.CreatedAt = types.StringValue(workspace.CreatedAt)
+	m.Public = types.BoolValue(workspace.Public)
+	m.App = types.StringValue(workspace.App)
+	m.StartAt = optionalString(workspace.StartAt, m.StartAt)
+	m.EndAt = optionalString(workspace.EndAt, m.EndAt)
+	m.Category = optionalString(workspace.Category, m.Category)
+	m.Aoi = optionalString(workspace.Aoi, m.Aoi)
+	m.ViewAccess = types.StringValue(workspace.ViewAccess)
+	m.EditAccess = types.StringValue(workspace.EditAccess)
+	// The password is write-only, the states written before it was may
+	// still hold it.
+	m.Password = types.StringNull()
+	m.EditorUserGroups, d = optionalInt64Set(ctx, workspace.EditorUserGroups, m.EditorUserGroups)
+	diags.Append(d...)
 
 	if workspace.Viewport != nil {
-		configuration := flattenWorkspaceViewport(*workspace.Viewport)
-		if err := d.Set("viewport", []interface{}{configuration}); err != nil {
-			return diag.FromErr(err)
-		}
+		m.Viewport, d = flattenWorkspaceViewport(*workspace.Viewport, m.Viewport)
+		diags.Append(d...)
 	}
 	if workspace.State != nil {
-		jsonStr, err := json.Marshal(workspace.State)
-		if err != nil {
-			return diag.FromErr(err)
+		state := *workspace.State
+		if len(m.StateConfig.Elements()) > 0 {
+			var stateConfig map[string]interface{}
+			state, stateConfig = splitWorkspaceState(state)
+			m.StateConfig, d = flattenWorkspaceStateConfig(stateConfig)
+			diags.Append(d...)
 		}
-		if err := d.Set("state", string(jsonStr)); err != nil {
-			return diag.FromErr(err)
+		body := []byte{}
+		if len(state) > 0 || m.State.ValueString() != "" {
+			body, err = json.Marshal(state)
+			if err != nil {
+				diags.AddError("Unable to read workspace state", err.Error())
+				return true
+			}
 		}
+		m.State = optionalJSON(string(body), m.State)
 	}
 	if workspace.DataviewInstances != nil {
-		dataviewInstances, err := flattenWorkspaceDataviewInstances(*workspace.DataviewInstances)
-		if err != nil {
-			return diag.FromErr(err)
+		instances := *workspace.DataviewInstances
+		if m.IgnoreExternalDataviewInstances.ValueBool() {
+			instances = managedWorkspaceDataviewInstances(instances, sdkValue(m.DataviewInstances).([]interface{}))
 		}
-		if err := d.Set("dataview_instances", dataviewInstances); err != nil {
-			return diag.FromErr(err)
+		dataviewInstances, err := flattenWorkspaceDataviewInstances(instances)
+		if err != nil {
+			diags.AddError("Unable to read workspace dataview instances", err.Error())
+			return true
 		}
+		m.DataviewInstances, d = workspaceDataviewInstancesToModel(ctx, dataviewInstances, m.DataviewInstances)
+		diags.Append(d...)
 	}
-
-	return diags
+	return true
 }
 
-func resourceWorkspaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	workspace, err := schemaToWorkspace(d)
+func (r *workspaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state, config workspaceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, diags := plan.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	workspace, err := modelToWorkspace(ctx, plan, config, false)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Unable to update workspace", err.Error())
+		return
 	}
-	workspaceId := d.Id()
-	c := m.(*api.GFWClient)
-	err = c.UpdateWorkspace(workspaceId, workspace)
+	patch, err := patchFromPlan(req.Plan, req.State, workspace, workspacePatchKeys)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Unable to update workspace", err.Error())
+		return
+	}
+	workspaceId := state.ID.ValueString()
+	c := r.config.Client
+	// The instances written by gfw_workspace_dataview_instance are read and
+	// patched back, the lock is held until the patch is sent.
+	unlock := r.config.workspaceLocks.Lock(workspaceId)
+	defer unlock()
+	if _, ok := patch["dataviewInstances"]; ok && plan.IgnoreExternalDataviewInstances.ValueBool() {
+		previous := sdkValue(state.DataviewInstances).([]interface{})
+		configured := sdkValue(plan.DataviewInstances).([]interface{})
+		instances, err := withExternalWorkspaceDataviewInstances(c, workspaceId, workspace, previous, configured)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to update workspace", err.Error())
+			return
+		}
+		patch["dataviewInstances"] = instances
+	}
+	if len(patch) > 0 {
+		if err := c.UpdateWorkspace(workspaceId, patch); err != nil {
+			resp.Diagnostics.AddError("Unable to update workspace", err.Error())
+			return
+		}
 	}
-	return resourceWorkspaceRead(ctx, d, m)
+	resp.Diagnostics.Append(frameworkDiagnostics(enumWarnings(r.config, workspaceEnumValues(plan.Category.ValueString())))...)
+	plan.ID = state.ID
+	r.read(ctx, &plan, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func schemaToWorkspace(d *schema.ResourceData) (api.CreateWorkspace, error) {
-	workspace := api.CreateWorkspace{}
-	if d.HasChange("name") {
-		workspace.Name = d.Get("name").(string)
+func (r *workspaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state workspaceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	timeout, diags := state.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(diags...)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp.Diagnostics.Append(frameworkDiagnostics(deleteWorkspace(r.config.Client, state.ID.ValueString(), state.DeletionProtection.ValueBool()))...)
+}
+
+// deleteWorkspace deletes a workspace unless it is protected, for both
+// gfw_workspace and gfw_workspace_copy.
+func deleteWorkspace(c *api.GFWClient, id string, protected bool) diag.Diagnostics {
+	if diags := deletionProtectionDiagnostics(protected, "workspace", id); diags.HasError() {
+		return diags
+	}
+	if _, err := c.DeleteWorkspace(id); err != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Unable to delete workspace",
+			Detail:   err.Error(),
+		}}
+	}
+	return nil
+}
+
+// ImportState imports a workspace by its ID. Attributes kept in the state
+// only are given their default, as importStateWithDefaults does.
+func (r *workspaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	for _, key := range []string{"deletion_protection", "public", "ignore_external_dataview_instances"} {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(key), false)...)
+	}
+}
+
+// ModifyPlan validates the planned workspace, as CustomizeDiff does for the
+// SDKv2 resources.
+func (r *workspaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.config == nil {
+		return
+	}
+	var plan workspaceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	config := r.config
+
+	if err := validateEnumValues(config, workspaceEnumValues(plan.Category.ValueString())); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("category"), "Invalid workspace category", err.Error())
 	}
-	if d.HasChange("description") {
-		workspace.Description = d.Get("description").(string)
+	// A new workspace without workspace_id gets its ID from its name, so it
+	// is known at plan time. Flipping public replaces the workspace.
+	if req.State.Raw.IsNull() && plan.WorkspaceID.IsUnknown() && !plan.Name.IsUnknown() && !plan.Public.IsUnknown() {
+		id := api.WorkspaceID(plan.Name.ValueString(), plan.Public.ValueBool())
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("workspace_id"), id)...)
 	}
-	if d.HasChange("app") {
-		workspace.App = d.Get("app").(string)
+	if viewport := listObjects(plan.Viewport); knownList(plan.Viewport) && len(viewport) > 0 {
+		zoom := sdkValue(viewport[0]).(map[string]interface{})["zoom"].(float64)
+		if err := validateWorkspaceViewport(config, zoom); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("viewport"), "Invalid viewport", err.Error())
+		}
+	}
+	if !plan.StartAt.IsUnknown() && !plan.EndAt.IsUnknown() {
+		resp.Diagnostics.Append(validateWorkspaceTimeRange(plan.StartAt.ValueString(), plan.EndAt.ValueString())...)
 	}
-	if d.HasChange("category") {
-		workspace.Category = d.Get("category").(string)
+	if !plan.State.IsUnknown() && !plan.StateConfig.IsUnknown() {
+		if err := validateWorkspaceState(plan.State.ValueString(), len(plan.StateConfig.Elements()) > 0); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("state"), "Invalid workspace state", err.Error())
+		}
 	}
-	if d.HasChange("public") {
-		workspace.Public = d.Get("public").(bool)
+	// The password is write-only, it is null in the plan.
+	var password types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password"), &password)...)
+	if !password.IsUnknown() && !plan.ViewAccess.IsUnknown() && !plan.EditAccess.IsUnknown() {
+		if err := validateWorkspaceAccess(password.ValueString(), plan.ViewAccess.ValueString(), plan.EditAccess.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("password"), "Missing workspace password", err.Error())
+		}
 	}
-	if d.HasChange("start_at") {
-		workspace.StartAt = d.Get("start_at").(string)
+	if !config.ValidateReferences {
+		return
 	}
-	if d.HasChange("end_at") {
-		workspace.EndAt = d.Get("end_at").(string)
+	// Every reference is checked, a dataset deleted since the last apply
+	// fails the plan of the resources using it. Unlike CustomizeDiff, the
+	// plan reports the warnings too, so Read does not check references.
+	if !plan.Aoi.IsUnknown() {
+		resp.Diagnostics.Append(frameworkDiagnostics(checkWorkspaceAoi(config, plan.Aoi.ValueString()))...)
 	}
-	if d.HasChange("aoi") {
-		workspace.Aoi = d.Get("aoi").(string)
+	if !knownList(plan.DataviewInstances) {
+		return
 	}
-	if d.HasChange("dataviews") {
-		workspace.Dataviews = utils.ConvertArrayInterfaceToArrayInt(d.Get("dataviews").([]interface{}))
+	dataviewRefs, datasetRefs := workspaceReferences(sdkValue(plan.DataviewInstances).([]interface{}))
+	resp.Diagnostics.Append(frameworkDiagnostics(checkDataviewReferences(config, dataviewRefs))...)
+	_, diags := checkDatasetReferences(config, datasetRefs)
+	resp.Diagnostics.Append(frameworkDiagnostics(diags)...)
+}
+
+// modelToWorkspace returns the complete desired state of the workspace. The
+// configuration tells the attributes of state_config that are set.
+func modelToWorkspace(ctx context.Context, plan workspaceModel, config workspaceModel, create bool) (api.CreateWorkspace, error) {
+	workspace := api.CreateWorkspace{}
+	workspace.Name = plan.Name.ValueString()
+	workspace.Description = modelToNullableString(plan.Description, create)
+	workspace.App = plan.App.ValueString()
+	workspace.Category = modelToNullableString(plan.Category, create)
+	public := plan.Public.ValueBool()
+	workspace.Public = &public
+	workspace.StartAt = modelToNullableString(plan.StartAt, create)
+	workspace.EndAt = modelToNullableString(plan.EndAt, create)
+	workspace.Aoi = modelToNullableString(plan.Aoi, create)
+	workspace.Dataviews = utils.ConvertArrayInterfaceToArrayInt(sdkValue(plan.Dataviews).([]interface{}))
+
+	if viewport := listObjects(plan.Viewport); len(viewport) > 0 {
+		workspace.Viewport = api.NewNullable(schemaToWorkspaceViewport(sdkValue(viewport[0]).(map[string]interface{})))
+	} else if !create {
+		workspace.Viewport = api.NewNull[api.WorkspaceViewport]()
 	}
+	workspace.ViewAccess = plan.ViewAccess.ValueString()
+	workspace.EditAccess = plan.EditAccess.ValueString()
+	workspace.Password = modelToNullableString(config.Password, create)
+	editors := utils.ConvertArrayInterfaceToArrayInt(sdkValue(plan.EditorUserGroups).([]interface{}))
+	workspace.EditorUserGroups = &editors
 
-	if d.HasChange("viewport") && d.Get("viewport") != nil {
-		viewport := d.Get("viewport").([]interface{})
-		if len(viewport) > 0 {
-			viewportObj := schemaToWorkspaceViewport(viewport[0].(map[string]interface{}))
-			workspace.Viewport = &viewportObj
+	var stateConfig map[string]interface{}
+	configured := map[string]bool{}
+	if typed := listObjects(plan.StateConfig); len(typed) > 0 {
+		stateConfig = sdkValue(typed[0]).(map[string]interface{})
+		if configuredTyped := listObjects(config.StateConfig); len(configuredTyped) > 0 {
+			for attribute, value := range configuredTyped[0].Attributes() {
+				configured[attribute] = !value.IsNull()
+			}
 		}
 	}
-	if d.HasChange("state") && d.Get("state") != nil {
-		var obj map[string]interface{}
-		err := json.Unmarshal([]byte(d.Get("state").(string)), &obj)
+	state, err := schemaToWorkspaceState(plan.State.ValueString(), stateConfig, configured)
+	if err != nil {
+		return api.CreateWorkspace{}, err
+	}
+	if state != nil {
+		workspace.State = &state
+	}
+	dataviewInstances := sdkValue(plan.DataviewInstances).([]interface{})
+	if len(dataviewInstances) > 0 {
+		dataviewInstancesObj, err := schemaToWorkspaceDataviewInstances(dataviewInstances)
 		if err != nil {
 			return api.CreateWorkspace{}, err
 		}
-		workspace.State = &obj
+		workspace.DataviewInstances = &dataviewInstancesObj
 	}
-	if d.HasChange("dataview_instances") && d.Get("dataview_instances") != nil {
-		dataviewInstances := d.Get("dataview_instances").([]interface{})
-		if len(dataviewInstances) > 0 {
-			dataviewInstancesObj, err := schemaToWorkspaceDataviewInstances(dataviewInstances)
-			if err != nil {
-				return api.CreateWorkspace{}, err
-			}
-			workspace.DataviewInstances = &dataviewInstancesObj
+
+	return workspace, nil
+}
+
+// workspacePatchKeys maps the attributes that can be updated in place to
+// their JSON key in the PATCH body. The write-only password is sent when
+// password_version changes.
+var workspacePatchKeys = map[string]string{
+	"name":               "name",
+	"description":        "description",
+	"app":                "app",
+	"category":           "category",
+	"start_at":           "startAt",
+	"end_at":             "endAt",
+	"aoi":                "aoi",
+	"viewport":           "viewport",
+	"state":              "state",
+	"state_config":       "state",
+	"view_access":        "viewAccess",
+	"edit_access":        "editAccess",
+	"password_version":   "password",
+	"editor_user_groups": "editorUserGroups",
+	"dataviews":          "dataviews",
+	"dataview_instances": "dataviewInstances",
+}
+
+// managedWorkspaceDataviewInstances keeps the instances listed in the state.
+func managedWorkspaceDataviewInstances(instances []api.WorkspaceDataviewInstance, managed []interface{}) []api.WorkspaceDataviewInstance {
+	ids := workspaceDataviewInstanceIds(managed)
+	list := []api.WorkspaceDataviewInstance{}
+	for _, instance := range instances {
+		if ids[instance.ID] {
+			list = append(list, instance)
 		}
 	}
+	return list
+}
 
-	return workspace, nil
+// withExternalWorkspaceDataviewInstances returns the configured instances
+// followed by the instances of the workspace that were never listed in
+// dataview_instances, so updating the list does not remove them.
+func withExternalWorkspaceDataviewInstances(c *api.GFWClient, workspaceId string, workspace api.CreateWorkspace, previous []interface{}, configured []interface{}) ([]api.WorkspaceDataviewInstance, error) {
+	current, err := c.GetWorkspace(workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	instances := []api.WorkspaceDataviewInstance{}
+	if workspace.DataviewInstances != nil {
+		instances = append(instances, *workspace.DataviewInstances...)
+	}
+	if current.DataviewInstances == nil {
+		return instances, nil
+	}
+	managed := workspaceDataviewInstanceIds(previous)
+	for id := range workspaceDataviewInstanceIds(configured) {
+		managed[id] = true
+	}
+	for _, instance := range *current.DataviewInstances {
+		if !managed[instance.ID] {
+			instances = append(instances, instance)
+		}
+	}
+	return instances, nil
+}
+
+func workspaceDataviewInstanceIds(instances []interface{}) map[string]bool {
+	ids := map[string]bool{}
+	for _, inter := range instances {
+		if mp, ok := inter.(map[string]interface{}); ok {
+			ids[mp["id"].(string)] = true
+		}
+	}
+	return ids
 }
 
 func schemaToWorkspaceViewport(schema map[string]interface{}) api.WorkspaceViewport {
 	config := api.WorkspaceViewport{
 		Zoom:      schema["zoom"].(float64),
 		Latitude:  schema["latitude"].(float64),
-		Longitude: schema["longitude"].(float64),
+		Longitude: normalizeLongitude(schema["longitude"].(float64)),
 	}
 
 	return config
@@ -336,29 +919,67 @@ func schemaToWorkspaceDataviewInstances(schema []interface{}) ([]api.WorkspaceDa
 	return list, nil
 }
 
-func resourceWorkspaceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
-	workspaceId := d.Id()
-
-	c := m.(*api.GFWClient)
-	_, err := c.DeleteWorkspace(workspaceId)
-	if err != nil {
-		return diag.FromErr(err)
+// flattenWorkspaceViewport keeps the prior longitude when it is equivalent to
+// the normalised one returned by the API.
+func flattenWorkspaceViewport(config api.WorkspaceViewport, prior types.List) (types.List, fwdiag.Diagnostics) {
+	longitude := config.Longitude
+	if viewport := listObjects(prior); len(viewport) > 0 {
+		if p, ok := viewport[0].Attributes()["longitude"].(types.Float64); ok && !p.IsNull() && !p.IsUnknown() && normalizeLongitude(p.ValueFloat64()) == longitude {
+			longitude = p.ValueFloat64()
+		}
 	}
-
-	return diags
+	objectType := types.ObjectType{AttrTypes: WORKSPACE_VIEWPORT_ATTRIBUTE_TYPES}
+	object, diags := types.ObjectValue(WORKSPACE_VIEWPORT_ATTRIBUTE_TYPES, map[string]attr.Value{
+		"zoom":      types.Float64Value(config.Zoom),
+		"latitude":  types.Float64Value(config.Latitude),
+		"longitude": types.Float64Value(longitude),
+	})
+	if diags.HasError() {
+		return types.ListNull(objectType), diags
+	}
+	return types.ListValue(objectType, []attr.Value{object})
 }
 
-func flattenWorkspaceViewport(config api.WorkspaceViewport) interface{} {
-	a := make(map[string]interface{})
-
-	a["zoom"] = config.Zoom
-	a["latitude"] = config.Latitude
-	a["longitude"] = config.Longitude
-
-	return a
+// flattenWorkspaceStateConfig returns the state_config block of the keys split
+// out of the workspace state, the missing ones being null.
+func flattenWorkspaceStateConfig(stateConfig map[string]interface{}) (types.List, fwdiag.Diagnostics) {
+	attributes := map[string]attr.Value{}
+	for attribute, attributeType := range WORKSPACE_STATE_CONFIG_ATTRIBUTE_TYPES {
+		value := stateConfig[attribute]
+		switch attributeType {
+		case types.BoolType:
+			if b, ok := value.(bool); ok {
+				attributes[attribute] = types.BoolValue(b)
+			} else {
+				attributes[attribute] = types.BoolNull()
+			}
+		case types.StringType:
+			if s, ok := value.(string); ok {
+				attributes[attribute] = types.StringValue(s)
+			} else {
+				attributes[attribute] = types.StringNull()
+			}
+		default:
+			list, ok := value.([]interface{})
+			if !ok {
+				attributes[attribute] = types.ListNull(types.StringType)
+				continue
+			}
+			elements := make([]attr.Value, len(list))
+			for i, e := range list {
+				elements[i] = types.StringValue(fmt.Sprint(e))
+			}
+			attributes[attribute] = types.ListValueMust(types.StringType, elements)
+		}
+	}
+	objectType := types.ObjectType{AttrTypes: WORKSPACE_STATE_CONFIG_ATTRIBUTE_TYPES}
+	object, diags := types.ObjectValue(WORKSPACE_STATE_CONFIG_ATTRIBUTE_TYPES, attributes)
+	if diags.HasError() {
+		return types.ListNull(objectType), diags
+	}
+	return types.ListValue(objectType, []attr.Value{object})
 }
+
 func flattenWorkspaceDataviewInstances(dataviewInstances []api.WorkspaceDataviewInstance) ([]interface{}, error) {
 	list := make([]interface{}, len(dataviewInstances))
 	for i, di := range dataviewInstances {
@@ -390,3 +1011,238 @@ func flattenWorkspaceDataviewInstances(dataviewInstances []api.WorkspaceDataview
 	}
 	return list, nil
 }
+
+// workspaceDataviewInstancesToModel turns flattened dataview instances into
+// the dataview_instances blocks, compared by position with the prior ones.
+func workspaceDataviewInstancesToModel(ctx context.Context, dataviewInstances []interface{}, prior types.List) (types.List, fwdiag.Diagnostics) {
+	var diags fwdiag.Diagnostics
+	objectType := types.ObjectType{AttrTypes: WORKSPACE_DATAVIEW_INSTANCE_ATTRIBUTE_TYPES}
+	priorInstances := listObjects(prior)
+	elements := make([]attr.Value, len(dataviewInstances))
+	for i, inter := range dataviewInstances {
+		mp := inter.(map[string]interface{})
+		var priorAttributes map[string]attr.Value
+		if i < len(priorInstances) {
+			priorAttributes = priorInstances[i].Attributes()
+		}
+		config, _ := mp["config"].(string)
+		datasetsConfigValues, _ := mp["datasets_config"].([]string)
+		datasetsConfig, d := optionalStringList(ctx, datasetsConfigValues, priorList(priorAttributes, "datasets_config", types.StringType))
+		diags.Append(d...)
+		elements[i], d = types.ObjectValue(WORKSPACE_DATAVIEW_INSTANCE_ATTRIBUTE_TYPES, map[string]attr.Value{
+			"id":              types.StringValue(mp["id"].(string)),
+			"category":        optionalString(mp["category"].(string), priorString(priorAttributes, "category")),
+			"config":          optionalJSON(config, priorString(priorAttributes, "config")),
+			"dataview_id":     types.StringValue(mp["dataview_id"].(string)),
+			"datasets_config": datasetsConfig,
+		})
+		diags.Append(d...)
+	}
+	if diags.HasError() {
+		return types.ListNull(objectType), diags
+	}
+	list, d := types.ListValue(objectType, elements)
+	diags.Append(d...)
+	return list, diags
+}
+
+func workspaceEnumValues(category string) []enumValue {
+	return []enumValue{
+		{Path: "category", Enum: ENUM_WORKSPACE_CATEGORIES, Value: category},
+	}
+}
+
+// workspaceReferences returns the dataviews used by the dataview instances and
+// the datasets set through their datasets_config datasetId.
+func workspaceReferences(dataviewInstances []interface{}) ([]reference, []reference) {
+	dataviewRefs := []reference{}
+	datasetRefs := []reference{}
+	for i, inter := range dataviewInstances {
+		if inter == nil {
+			continue
+		}
+		mp := inter.(map[string]interface{})
+		if id, ok := mp["dataview_id"].(string); ok && id != "" {
+			dataviewRefs = append(dataviewRefs, reference{Path: fmt.Sprintf("dataview_instances.%d.dataview_id", i), ID: id})
+		}
+		datasetsConfig, ok := mp["datasets_config"].([]interface{})
+		if !ok {
+			if list, isStrings := mp["datasets_config"].([]string); isStrings {
+				for _, s := range list {
+					datasetsConfig = append(datasetsConfig, s)
+				}
+			}
+		}
+		for j, dc := range datasetsConfig {
+			var obj map[string]interface{}
+			if dc == nil || json.Unmarshal([]byte(dc.(string)), &obj) != nil {
+				continue
+			}
+			if id, ok := obj["datasetId"].(string); ok && id != "" {
+				datasetRefs = append(datasetRefs, reference{Path: fmt.Sprintf("dataview_instances.%d.datasets_config.%d.datasetId", i, j), ID: id})
+			}
+		}
+	}
+	return dataviewRefs, datasetRefs
+}
+
+// normalizeLongitude wraps a longitude into [-180, 180].
+func normalizeLongitude(longitude float64) float64 {
+	if longitude >= -180 && longitude <= 180 {
+		return longitude
+	}
+	normalized := math.Mod(longitude+180, 360)
+	if normalized < 0 {
+		normalized += 360
+	}
+	return normalized - 180
+}
+
+// validateWorkspaceViewport checks the zoom against the range configured in
+// the provider.
+func validateWorkspaceViewport(config *Config, zoom float64) error {
+	if zoom < config.ViewportMinZoom || zoom > config.ViewportMaxZoom {
+		return fmt.Errorf("expected viewport.0.zoom to be in the range (%v - %v), got %v", config.ViewportMinZoom, config.ViewportMaxZoom, zoom)
+	}
+	return nil
+}
+
+// validateWorkspaceTimeRange checks start_at and end_at once known, the
+// validators of the attributes do not run on values unknown in the
+// configuration.
+func validateWorkspaceTimeRange(startAt, endAt string) fwdiag.Diagnostics {
+	var diags fwdiag.Diagnostics
+	start, err := time.Parse("2006-01-02T15:04:05Z0700", startAt)
+	if startAt != "" && err != nil {
+		diags.AddAttributeError(path.Root("start_at"), "Invalid start_at", fmt.Sprintf("expected start_at to be a valid ISO8601 date, got %q: %v", startAt, err))
+	}
+	end, err := time.Parse("2006-01-02T15:04:05Z0700", endAt)
+	if endAt != "" && err != nil {
+		diags.AddAttributeError(path.Root("end_at"), "Invalid end_at", fmt.Sprintf("expected end_at to be a valid ISO8601 date, got %q: %v", endAt, err))
+	}
+	if startAt == "" || endAt == "" || diags.HasError() {
+		return diags
+	}
+	if !start.Before(end) {
+		diags.AddAttributeError(path.Root("end_at"), "Invalid time range", fmt.Sprintf("expected start_at %q to be before end_at %q", startAt, endAt))
+	}
+	return diags
+}
+
+// checkWorkspaceAoi resolves the dataset of an AOI, given as
+// <dataset_id>/<area_id>, and checks it is a context layer holding areas.
+// Other values are accepted by the API, they are only warned about.
+func checkWorkspaceAoi(config *Config, aoi string) diag.Diagnostics {
+	if aoi == "" {
+		return nil
+	}
+	i := strings.LastIndex(aoi, "/")
+	if i <= 0 || i == len(aoi)-1 {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "AOI not checked",
+			Detail:   fmt.Sprintf("aoi %q is not <dataset_id>/<area_id>, its dataset can not be checked", aoi),
+		}}
+	}
+	datasetId := aoi[:i]
+	datasets, diags := checkDatasetReferences(config, []reference{{Path: "aoi", ID: datasetId}})
+	dataset, ok := datasets[datasetId]
+	if !ok {
+		return diags
+	}
+	if !utils.ContainsString(AOI_DATASET_TYPES, dataset.Type) && dataset.Category != "context-layer" {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "AOI dataset is not a context layer",
+			Detail:   fmt.Sprintf("aoi references dataset %q of type %q, expected one of %v", datasetId, dataset.Type, AOI_DATASET_TYPES),
+		})
+	}
+	return diags
+}
+
+// workspaceStateKeys maps the attributes of state_config to their key in the
+// workspace state JSON.
+var workspaceStateKeys = map[string]string{
+	"timebar_visualisation": "timebarVisualisation",
+	"timebar_graph":         "timebarGraph",
+	"bivariate_dataviews":   "bivariateDataviews",
+	"sidebar_open":          "sidebarOpen",
+	"report_dataset_id":     "reportDatasetId",
+	"report_area_id":        "reportAreaId",
+}
+
+// schemaToWorkspaceState merges the attributes of state_config written in the
+// configuration into the raw state JSON, so unset booleans and strings are not
+// sent as their zero value, and removes the keys of the unset ones. Keys
+// unknown to state_config are kept as they are.
+func schemaToWorkspaceState(raw string, stateConfig map[string]interface{}, configured map[string]bool) (map[string]interface{}, error) {
+	var state map[string]interface{}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &state); err != nil {
+			return nil, err
+		}
+	}
+	if stateConfig == nil {
+		return state, nil
+	}
+	if state == nil {
+		state = map[string]interface{}{}
+	}
+	for attribute, key := range workspaceStateKeys {
+		if configured[attribute] {
+			state[key] = stateConfig[attribute]
+		} else {
+			delete(state, key)
+		}
+	}
+	return state, nil
+}
+
+// splitWorkspaceState moves the keys known to state_config out of the state
+// JSON, returning the remaining state and the state_config block.
+func splitWorkspaceState(state map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	remaining := map[string]interface{}{}
+	stateConfig := map[string]interface{}{}
+	for key, value := range state {
+		remaining[key] = value
+	}
+	for attribute, key := range workspaceStateKeys {
+		if value, ok := remaining[key]; ok {
+			stateConfig[attribute] = value
+			delete(remaining, key)
+		}
+	}
+	return remaining, stateConfig
+}
+
+// validateWorkspaceState fails when a key managed by state_config is also set
+// in the raw state JSON.
+func validateWorkspaceState(raw string, stateConfig bool) error {
+	if !stateConfig || raw == "" {
+		return nil
+	}
+	var state map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil
+	}
+	for attribute, key := range workspaceStateKeys {
+		if _, ok := state[key]; ok {
+			return fmt.Errorf("state key %q is managed by state_config.0.%s, remove it from state", key, attribute)
+		}
+	}
+	return nil
+}
+
+// validateWorkspaceAccess requires a password when the view or edit access is
+// password protected.
+func validateWorkspaceAccess(password, viewAccess, editAccess string) error {
+	if password != "" {
+		return nil
+	}
+	for key, access := range map[string]string{"view_access": viewAccess, "edit_access": editAccess} {
+		if access == "password" {
+			return fmt.Errorf("password is required when %s is password", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,371 @@
+package gfw
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceWorkspaceCopy keeps a workspace in sync with a source workspace,
+// possibly read from another environment, rewriting its dataset and dataview
+// references through mapping tables. source_hash is the hash of the copy as
+// read from the target, changes of the source or of the target show up in the
+// plan as a change of source_hash.
+func resourceWorkspaceCopy() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Workspace kept in sync with a source workspace, possibly read from another API, with its dataset and dataview references rewritten through `dataset_mapping` and `dataview_mapping`. Changes of the source or of the copy are planned as a change of `source_hash`. An existing target workspace is not overwritten, it has to be imported with the ID `<source_workspace_id>/<workspace_id>`.",
+		CreateContext: resourceWorkspaceCopyCreate,
+		ReadContext:   resourceWorkspaceCopyRead,
+		UpdateContext: resourceWorkspaceCopyUpdate,
+		DeleteContext: resourceWorkspaceCopyDelete,
+		CustomizeDiff: resourceWorkspaceCopyCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWorkspaceCopyImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"deletion_protection": deletionProtectionSchema(false),
+			"source_workspace_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Workspace copied.",
+			},
+			// The source is read with the provider credentials unless a
+			// different API is given.
+			"source_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"source_token"},
+				Description:  "API the source is read from, the one of the provider when unset.",
+			},
+			"source_token": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"source_url"},
+				Description:  "Token of the API given in `source_url`.",
+			},
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "ID of the copy, derived from its name when unset.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of the copy, the one of the source when unset.",
+			},
+			"public": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+			"dataset_mapping": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Dataset IDs of the copy keyed by the dataset IDs of the source, IDs without mapping are kept.",
+			},
+			"dataview_mapping": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Dataview IDs of the copy keyed by the dataview IDs of the source, IDs without mapping are kept.",
+			},
+			"source_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Hash of the copy as read from the target.",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceWorkspaceCopyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	c := m.(*Config).Client
+	var diags diag.Diagnostics
+
+	workspace, _, err := workspaceCopyFromSource(m.(*Config), d, api.OptionalString)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	workspace.ID = d.Get("workspace_id").(string)
+	if workspace.ID == "" {
+		workspace.ID = api.WorkspaceID(workspace.Name, d.Get("public").(bool))
+	}
+
+	_, err = c.CreateWorkspace(workspace)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(workspace.ID)
+	diags = append(diags, resourceWorkspaceCopyRead(ctx, d, m)...)
+	return diags
+}
+
+func resourceWorkspaceCopyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	c := m.(*Config).Client
+	workspace, err := c.GetWorkspace(d.Id())
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	d.Set("workspace_id", workspace.ID)
+	d.Set("name", workspace.Name)
+	d.Set("public", workspace.Public)
+
+	copied, err := copyWorkspace(*workspace, nil, nil, api.OptionalString)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	copied.Public = &workspace.Public
+	hash, err := workspaceCopyHash(copied)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("source_hash", hash)
+
+	return diags
+}
+
+func resourceWorkspaceCopyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.HasChangesExcept("deletion_protection") {
+		return resourceWorkspaceCopyRead(ctx, d, m)
+	}
+	c := m.(*Config).Client
+	workspace, _, err := workspaceCopyFromSource(m.(*Config), d, api.NullableString)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	err = updateWorkspaceCopy(c, d.Id(), workspace)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceWorkspaceCopyRead(ctx, d, m)
+}
+
+func resourceWorkspaceCopyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteWorkspace(m.(*Config).Client, d.Id(), d.Get("deletion_protection").(bool))
+}
+
+// The import ID is <source_workspace_id>/<workspace_id>, the source can not be
+// read from the copy. The next apply overwrites the target with the copy.
+func resourceWorkspaceCopyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("unexpected ID %q, expected <source_workspace_id>/<workspace_id>", d.Id())
+	}
+	d.SetId(parts[1])
+	if err := d.Set("source_workspace_id", parts[0]); err != nil {
+		return nil, err
+	}
+	return importStateWithDefaults(resourceWorkspaceCopy)(ctx, d, m)
+}
+
+// resourceWorkspaceCopyCustomizeDiff compares the copy of the source with the
+// target read on refresh, so changes of either are planned as an update.
+func resourceWorkspaceCopyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, key := range []string{"source_workspace_id", "source_url", "source_token", "name", "dataset_mapping", "dataview_mapping"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("source_hash")
+		}
+	}
+	_, hash, err := workspaceCopyFromSource(m.(*Config), d, api.OptionalString)
+	if err != nil {
+		return err
+	}
+	if hash != d.Get("source_hash").(string) {
+		return d.SetNew("source_hash", hash)
+	}
+	return nil
+}
+
+// updateWorkspaceCopy overwrites every attribute of the target workspace.
+func updateWorkspaceCopy(c *api.GFWClient, id string, workspace api.CreateWorkspace) error {
+	workspace.ID = ""
+	workspace.Public = nil
+	body, err := json.Marshal(workspace)
+	if err != nil {
+		return err
+	}
+	patch := api.Patch{}
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return err
+	}
+	return c.UpdateWorkspace(id, patch)
+}
+
+// workspaceCopyFromSource reads the source workspace and returns the payload
+// of the copy together with its hash. nullable decides how empty attributes of
+// the source are sent.
+func workspaceCopyFromSource(config *Config, d resourceGetter, nullable func(string) api.Nullable[string]) (api.CreateWorkspace, string, error) {
+	c := config.Client
+	if url := d.Get("source_url").(string); url != "" {
+		var err error
+		c, err = api.NewClient(url, d.Get("source_token").(string))
+		if err != nil {
+			return api.CreateWorkspace{}, "", err
+		}
+	}
+	source, err := c.GetWorkspace(d.Get("source_workspace_id").(string))
+	if err != nil {
+		return api.CreateWorkspace{}, "", fmt.Errorf("unable to read source workspace: %v", err)
+	}
+
+	copyOf := func(nullable func(string) api.Nullable[string]) (api.CreateWorkspace, error) {
+		workspace, err := copyWorkspace(*source, toStringMap(d.Get("dataset_mapping")), toStringMap(d.Get("dataview_mapping")), nullable)
+		if err != nil {
+			return api.CreateWorkspace{}, err
+		}
+		if name := d.Get("name").(string); name != "" {
+			workspace.Name = name
+		}
+		public := d.Get("public").(bool)
+		workspace.Public = &public
+		return workspace, nil
+	}
+
+	// The hash does not depend on how empty attributes are sent, so it is
+	// the same on create, update and plan.
+	hashed, err := copyOf(api.OptionalString)
+	if err != nil {
+		return api.CreateWorkspace{}, "", err
+	}
+	hash, err := workspaceCopyHash(hashed)
+	if err != nil {
+		return api.CreateWorkspace{}, "", err
+	}
+	workspace, err := copyOf(nullable)
+	if err != nil {
+		return api.CreateWorkspace{}, "", err
+	}
+	return workspace, hash, nil
+}
+
+// workspaceCopyHash returns the hash of the payload of a copy, built with
+// api.OptionalString for empty attributes.
+func workspaceCopyHash(workspace api.CreateWorkspace) (string, error) {
+	body, err := json.Marshal(workspace)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(body)), nil
+}
+
+// copyWorkspace builds the payload of a copy of the source workspace, mapping
+// the dataset and dataview IDs it references. IDs without mapping are kept.
+func copyWorkspace(source api.Workspace, datasets map[string]string, dataviews map[string]string, nullable func(string) api.Nullable[string]) (api.CreateWorkspace, error) {
+	dataset := func(id string) string {
+		if mapped, ok := datasets[id]; ok {
+			return mapped
+		}
+		return id
+	}
+	dataview := func(id string) string {
+		if mapped, ok := dataviews[id]; ok {
+			return mapped
+		}
+		return id
+	}
+
+	workspace := api.CreateWorkspace{
+		Name:        source.Name,
+		Description: nullable(source.Description),
+		Category:    nullable(source.Category),
+		App:         source.App,
+		StartAt:     nullable(source.StartAt),
+		EndAt:       nullable(source.EndAt),
+		State:       source.State,
+	}
+	if i := strings.LastIndex(source.Aoi, "/"); i > 0 {
+		workspace.Aoi = nullable(fmt.Sprintf("%s/%s", dataset(source.Aoi[:i]), source.Aoi[i+1:]))
+	} else {
+		workspace.Aoi = nullable(source.Aoi)
+	}
+	if source.Viewport != nil {
+		workspace.Viewport = api.NewNullable(*source.Viewport)
+	} else if nullable("").IsNull() {
+		workspace.Viewport = api.NewNull[api.WorkspaceViewport]()
+	}
+
+	if source.DataviewInstances != nil {
+		instances := make([]api.WorkspaceDataviewInstance, len(*source.DataviewInstances))
+		for i, instance := range *source.DataviewInstances {
+			instance.DataviewID = dataview(instance.DataviewID)
+			if instance.Config != nil {
+				config := map[string]interface{}{}
+				for k, v := range *instance.Config {
+					config[k] = v
+				}
+				if list, ok := config["datasets"].([]interface{}); ok {
+					mapped := make([]interface{}, len(list))
+					for j, id := range list {
+						if s, ok := id.(string); ok {
+							mapped[j] = dataset(s)
+						} else {
+							mapped[j] = id
+						}
+					}
+					config["datasets"] = mapped
+				}
+				instance.Config = &config
+			}
+			if instance.DatasetsConfig != nil {
+				datasetsConfig := make([]map[string]interface{}, len(instance.DatasetsConfig))
+				for j, dc := range instance.DatasetsConfig {
+					mapped := map[string]interface{}{}
+					for k, v := range dc {
+						mapped[k] = v
+					}
+					if id, ok := mapped["datasetId"].(string); ok {
+						mapped["datasetId"] = dataset(id)
+					}
+					datasetsConfig[j] = mapped
+				}
+				instance.DatasetsConfig = datasetsConfig
+			}
+			instances[i] = instance
+		}
+		workspace.DataviewInstances = &instances
+	}
+
+	return workspace, nil
+}
+
+func toStringMap(i interface{}) map[string]string {
+	result := map[string]string{}
+	if mp, ok := i.(map[string]interface{}); ok {
+		for k, v := range mp {
+			result[k] = v.(string)
+		}
+	}
+	return result
+}
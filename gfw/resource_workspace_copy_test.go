@@ -0,0 +1,146 @@
+package gfw
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccWorkspaceCopy_basic(t *testing.T) {
+	s := testAccServer(t)
+	source := map[string]interface{}{
+		"id":          "source_workspace",
+		"name":        "Source workspace",
+		"description": "Workspace promoted by the acceptance tests",
+		"app":         "fishing-map",
+		"aoi":         "test-mpa:v1/42",
+		"dataviewInstances": []interface{}{
+			map[string]interface{}{
+				"id":         "context-mpa",
+				"dataviewId": "7",
+				"config":     map[string]interface{}{"datasets": []interface{}{"test-mpa:v1"}},
+			},
+		},
+	}
+	if _, err := s.Put(fake.Workspaces, source); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckDestroyed(s, fake.Workspaces, "gfw_workspace_copy"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_workspace_copy.test",
+			Collection: fake.Workspaces,
+			Create: resource.TestStep{
+				Config: testAccWorkspaceCopyConfig("Copied workspace"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_workspace_copy.test", "workspace_id", "copied_workspace"),
+					resource.TestCheckResourceAttrSet("gfw_workspace_copy.test", "source_hash"),
+					testAccCheckWorkspaceCopy(s, "copied_workspace", "test-mpa:v2/42"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccWorkspaceCopyConfig("Promoted workspace"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_workspace_copy.test", "workspace_id", "copied_workspace"),
+					resource.TestCheckResourceAttr("gfw_workspace_copy.test", "name", "Promoted workspace"),
+				),
+			},
+			ImportStateId:           "source_workspace/copied_workspace",
+			ImportStateVerifyIgnore: []string{"dataset_mapping"},
+			Steps: []resource.TestStep{
+				{
+					// Changes of the copy are planned as an update overwriting them.
+					Config: testAccWorkspaceCopyConfig("Promoted workspace"),
+					Check: func(state *terraform.State) error {
+						target, _ := s.Get(fake.Workspaces, "copied_workspace")
+						target["description"] = "Edited by hand"
+						_, err := s.Put(fake.Workspaces, target)
+						return err
+					},
+					ExpectNonEmptyPlan: true,
+				},
+				{
+					Config: testAccWorkspaceCopyConfig("Promoted workspace"),
+					Check: func(state *terraform.State) error {
+						target, _ := s.Get(fake.Workspaces, "copied_workspace")
+						if target["description"] != source["description"] {
+							return fmt.Errorf("expected the description of the copy to be restored, got %v", target["description"])
+						}
+						return nil
+					},
+				},
+				{
+					// Changes of the source are planned as an update of the copy.
+					Config: testAccWorkspaceCopyConfig("Promoted workspace"),
+					Check: func(state *terraform.State) error {
+						source["description"] = "Updated source"
+						_, err := s.Put(fake.Workspaces, source)
+						return err
+					},
+					ExpectNonEmptyPlan: true,
+				},
+			},
+		}),
+	})
+}
+
+// An existing target is not overwritten, it has to be imported.
+func TestAccWorkspaceCopy_existingTarget(t *testing.T) {
+	s := testAccServer(t)
+	for _, w := range []map[string]interface{}{
+		{"id": "source_workspace", "name": "Source workspace", "app": "fishing-map"},
+		{"id": "copied_workspace", "name": "Declared by hand", "app": "fishing-map"},
+	} {
+		if _, err := s.Put(fake.Workspaces, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccWorkspaceCopyConfig("Copied workspace"),
+				ExpectError: regexp.MustCompile(`workspace "copied_workspace" already exists, import it`),
+			},
+		},
+	})
+	if target, _ := s.Get(fake.Workspaces, "copied_workspace"); target["name"] != "Declared by hand" {
+		t.Errorf("existing target was overwritten: %v", target)
+	}
+}
+
+func testAccCheckWorkspaceCopy(s *fake.Server, id string, aoi string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		workspace, err := s.APIClient().GetWorkspace(id)
+		if err != nil {
+			return err
+		}
+		if workspace.Aoi != aoi {
+			return fmt.Errorf("expected aoi %q, got %q", aoi, workspace.Aoi)
+		}
+		instances := *workspace.DataviewInstances
+		datasets := (*instances[0].Config)["datasets"].([]interface{})
+		if datasets[0] != "test-mpa:v2" {
+			return fmt.Errorf("dataset of the dataview instance was not mapped: %v", datasets)
+		}
+		return nil
+	}
+}
+
+func testAccWorkspaceCopyConfig(name string) string {
+	return fmt.Sprintf(`
+resource "gfw_workspace_copy" "test" {
+  source_workspace_id = "source_workspace"
+  workspace_id        = "copied_workspace"
+  name                = %q
+  dataset_mapping = {
+    "test-mpa:v1" = "test-mpa:v2"
+  }
+}
+`, name)
+}
@@ -0,0 +1,239 @@
+package gfw
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceWorkspaceDataviewInstance owns a single dataview instance of a
+// workspace, so several configurations can add layers to the same workspace.
+// The workspace should set ignore_external_dataview_instances.
+func resourceWorkspaceDataviewInstance() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Single dataview instance of a workspace, so several configurations can add layers to the same workspace. The `gfw_workspace` holding it should set `ignore_external_dataview_instances`, the instances of the workspace are read and written back under a lock shared with it. The import ID is `<workspace_id>/<instance_id>`.",
+		CreateContext: resourceWorkspaceDataviewInstanceCreate,
+		ReadContext:   resourceWorkspaceDataviewInstanceRead,
+		UpdateContext: resourceWorkspaceDataviewInstanceUpdate,
+		DeleteContext: resourceWorkspaceDataviewInstanceDelete,
+		CustomizeDiff: resourceWorkspaceDataviewInstanceCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWorkspaceDataviewInstanceImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"instance_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"category": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"config": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "JSON configuration of the instance.",
+			},
+			"dataview_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"datasets_config": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsJSON,
+				},
+				Description: "JSON configuration of each dataset of the instance.",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceWorkspaceDataviewInstanceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	c := m.(*Config).Client
+	var diags diag.Diagnostics
+
+	workspaceId := d.Get("workspace_id").(string)
+	instance, err := schemaToWorkspaceDataviewInstance(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	unlock := m.(*Config).workspaceLocks.Lock(workspaceId)
+	defer unlock()
+	if _, err := c.GetWorkspaceDataviewInstance(workspaceId, instance.ID); err == nil {
+		return diag.Errorf("dataview instance %q already exists in workspace %q, import it to manage it", instance.ID, workspaceId)
+	} else if !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	err = c.SetWorkspaceDataviewInstance(workspaceId, instance)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(workspaceDataviewInstanceId(workspaceId, instance.ID))
+	diags = append(diags, resourceWorkspaceDataviewInstanceRead(ctx, d, m)...)
+	return diags
+}
+
+func resourceWorkspaceDataviewInstanceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	workspaceId, instanceId, err := parseWorkspaceDataviewInstanceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	c := m.(*Config).Client
+	instance, err := c.GetWorkspaceDataviewInstance(workspaceId, instanceId)
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return diags
+		}
+		return diag.FromErr(err)
+	}
+
+	flattened, err := flattenWorkspaceDataviewInstances([]api.WorkspaceDataviewInstance{*instance})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	mp := flattened[0].(map[string]interface{})
+	d.Set("workspace_id", workspaceId)
+	d.Set("instance_id", instance.ID)
+	d.Set("category", mp["category"])
+	d.Set("dataview_id", mp["dataview_id"])
+	d.Set("config", mp["config"])
+	d.Set("datasets_config", mp["datasets_config"])
+
+	if m.(*Config).ValidateReferences {
+		_, datasetRefs := workspaceDataviewInstanceReferences(d)
+		_, refDiags := checkDatasetReferences(m.(*Config), datasetRefs)
+		diags = append(diags, diagsWarnings(refDiags)...)
+	}
+
+	return diags
+}
+
+func resourceWorkspaceDataviewInstanceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	c := m.(*Config).Client
+	workspaceId := d.Get("workspace_id").(string)
+	instance, err := schemaToWorkspaceDataviewInstance(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	unlock := m.(*Config).workspaceLocks.Lock(workspaceId)
+	defer unlock()
+	err = c.SetWorkspaceDataviewInstance(workspaceId, instance)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceWorkspaceDataviewInstanceRead(ctx, d, m)
+}
+
+func resourceWorkspaceDataviewInstanceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Warning or errors can be collected in a slice type
+	var diags diag.Diagnostics
+	workspaceId, instanceId, err := parseWorkspaceDataviewInstanceId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	c := m.(*Config).Client
+	unlock := m.(*Config).workspaceLocks.Lock(workspaceId)
+	defer unlock()
+	err = c.RemoveWorkspaceDataviewInstance(workspaceId, instanceId)
+	if err != nil && !isNotFound(err) {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourceWorkspaceDataviewInstanceImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if _, _, err := parseWorkspaceDataviewInstanceId(d.Id()); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceWorkspaceDataviewInstanceCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config := m.(*Config)
+	if !config.ValidateReferences {
+		return nil
+	}
+	if !d.NewValueKnown("dataview_id") || !d.NewValueKnown("datasets_config") {
+		return nil
+	}
+	dataviewRefs, datasetRefs := workspaceDataviewInstanceReferences(d)
+	if err := diagsToPlanError(checkDataviewReferences(config, dataviewRefs)); err != nil {
+		return err
+	}
+	_, diags := checkDatasetReferences(config, datasetRefs)
+	return diagsToPlanError(diags)
+}
+
+// workspaceDataviewInstanceReferences reuses the workspace reference lookup,
+// reporting the paths of this resource.
+func workspaceDataviewInstanceReferences(d resourceGetter) ([]reference, []reference) {
+	dataviewRefs, datasetRefs := workspaceReferences([]interface{}{map[string]interface{}{
+		"dataview_id":     d.Get("dataview_id"),
+		"datasets_config": d.Get("datasets_config"),
+	}})
+	for i := range dataviewRefs {
+		dataviewRefs[i].Path = strings.TrimPrefix(dataviewRefs[i].Path, "dataview_instances.0.")
+	}
+	for i := range datasetRefs {
+		datasetRefs[i].Path = strings.TrimPrefix(datasetRefs[i].Path, "dataview_instances.0.")
+	}
+	return dataviewRefs, datasetRefs
+}
+
+func schemaToWorkspaceDataviewInstance(d *schema.ResourceData) (api.WorkspaceDataviewInstance, error) {
+	instances, err := schemaToWorkspaceDataviewInstances([]interface{}{map[string]interface{}{
+		"id":              d.Get("instance_id"),
+		"category":        d.Get("category"),
+		"config":          d.Get("config"),
+		"dataview_id":     d.Get("dataview_id"),
+		"datasets_config": d.Get("datasets_config"),
+	}})
+	if err != nil {
+		return api.WorkspaceDataviewInstance{}, err
+	}
+	return instances[0], nil
+}
+
+// The ID of the resource is <workspace_id>/<instance_id>.
+func workspaceDataviewInstanceId(workspaceId string, instanceId string) string {
+	return fmt.Sprintf("%s/%s", workspaceId, instanceId)
+}
+
+func parseWorkspaceDataviewInstanceId(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected ID %q, expected <workspace_id>/<instance_id>", id)
+	}
+	return parts[0], parts[1], nil
+}
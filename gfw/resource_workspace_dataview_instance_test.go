@@ -0,0 +1,177 @@
+package gfw
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccWorkspaceDataviewInstance_basic(t *testing.T) {
+	s := testAccServer(t)
+	if _, err := s.Put(fake.Dataviews, map[string]interface{}{"id": 7, "slug": "test-mpa"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Put(fake.Workspaces, map[string]interface{}{
+		"id": "test_workspace",
+		"dataviewInstances": []interface{}{
+			map[string]interface{}{"id": "basemap", "dataviewId": "7"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(state *terraform.State) error {
+			if _, err := s.APIClient().GetWorkspaceDataviewInstance("test_workspace", "context-mpa"); !isNotFound(err) {
+				return fmt.Errorf("dataview instance context-mpa still exists")
+			}
+			if _, err := s.APIClient().GetWorkspaceDataviewInstance("test_workspace", "basemap"); err != nil {
+				return fmt.Errorf("dataview instance basemap not managed by Terraform was removed: %v", err)
+			}
+			return nil
+		},
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name: "gfw_workspace_dataview_instance.test",
+			Create: resource.TestStep{
+				Config: testAccWorkspaceDataviewInstanceConfig(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_workspace_dataview_instance.test", "id", "test_workspace/context-mpa"),
+					resource.TestCheckResourceAttr("gfw_workspace_dataview_instance.test", "dataview_id", "7"),
+					resource.TestCheckResourceAttr("gfw_workspace_dataview_instance.test", "config", `{"visible":true}`),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccWorkspaceDataviewInstanceConfig(false),
+				Check:  resource.TestCheckResourceAttr("gfw_workspace_dataview_instance.test", "config", `{"visible":false}`),
+			},
+			Disappears: func(state *terraform.State) error {
+				return s.APIClient().RemoveWorkspaceDataviewInstance("test_workspace", "context-mpa")
+			},
+		}),
+	})
+}
+
+// Instances of the same workspace applied in parallel are all kept, each
+// write reads the list patched by the previous one.
+func TestAccWorkspaceDataviewInstance_parallel(t *testing.T) {
+	s := testAccServer(t)
+	if _, err := s.Put(fake.Dataviews, map[string]interface{}{"id": 7, "slug": "test-mpa"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Put(fake.Workspaces, map[string]interface{}{"id": "test_workspace"}); err != nil {
+		t.Fatal(err)
+	}
+	s.InjectFault(fake.Fault{Method: "PATCH", Path: "workspaces/test_workspace", Latency: 100 * time.Millisecond})
+	resource.Test(t, resource.TestCase{
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy: func(state *terraform.State) error {
+			workspace, err := s.APIClient().GetWorkspace("test_workspace")
+			if err != nil {
+				return err
+			}
+			if workspace.DataviewInstances != nil && len(*workspace.DataviewInstances) > 0 {
+				return fmt.Errorf("expected every dataview instance to be removed, got %v", *workspace.DataviewInstances)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceDataviewInstanceParallelConfig(),
+				Check: func(state *terraform.State) error {
+					workspace, err := s.APIClient().GetWorkspace("test_workspace")
+					if err != nil {
+						return err
+					}
+					if workspace.DataviewInstances == nil || len(*workspace.DataviewInstances) != 4 {
+						return fmt.Errorf("expected 4 dataview instances, got %v", workspace.DataviewInstances)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+// A gfw_workspace ignoring the external instances keeps the ones written in
+// parallel by gfw_workspace_dataview_instance while it is updated.
+func TestAccWorkspaceDataviewInstance_externalToWorkspace(t *testing.T) {
+	s := testAccServer(t)
+	if _, err := s.Put(fake.Dataviews, map[string]interface{}{"id": 7, "slug": "test-mpa"}); err != nil {
+		t.Fatal(err)
+	}
+	s.InjectFault(fake.Fault{Method: "PATCH", Path: "workspaces/test_workspace", Latency: 100 * time.Millisecond})
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(s, fake.Workspaces, "gfw_workspace"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceExternalInstancesConfig(true, 0),
+			},
+			{
+				Config: testAccWorkspaceExternalInstancesConfig(false, 3),
+				Check: func(state *terraform.State) error {
+					workspace, err := s.APIClient().GetWorkspace("test_workspace")
+					if err != nil {
+						return err
+					}
+					if workspace.DataviewInstances == nil || len(*workspace.DataviewInstances) != 4 {
+						return fmt.Errorf("expected 4 dataview instances, got %v", workspace.DataviewInstances)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testAccWorkspaceExternalInstancesConfig(visible bool, external int) string {
+	return fmt.Sprintf(`
+resource "gfw_workspace" "test" {
+  deletion_protection                = false
+  workspace_id                       = "test_workspace"
+  name                               = "Test workspace"
+  description                        = "Workspace used by the acceptance tests"
+  app                                = "fishing-map"
+  ignore_external_dataview_instances = true
+
+  dataview_instances {
+    id          = "managed"
+    dataview_id = "7"
+    config      = jsonencode({ visible = %t })
+  }
+}
+
+resource "gfw_workspace_dataview_instance" "test" {
+  count        = %d
+  workspace_id = "test_workspace"
+  instance_id  = "external-${count.index}"
+  dataview_id  = "7"
+}
+`, visible, external)
+}
+
+func testAccWorkspaceDataviewInstanceParallelConfig() string {
+	return `
+resource "gfw_workspace_dataview_instance" "test" {
+  count        = 4
+  workspace_id = "test_workspace"
+  instance_id  = "context-${count.index}"
+  dataview_id  = "7"
+}
+`
+}
+
+func testAccWorkspaceDataviewInstanceConfig(visible bool) string {
+	return fmt.Sprintf(`
+resource "gfw_workspace_dataview_instance" "test" {
+  workspace_id = "test_workspace"
+  instance_id  = "context-mpa"
+  dataview_id  = "7"
+  config       = jsonencode({ visible = %t })
+}
+`, visible)
+}
@@ -0,0 +1,255 @@
+package gfw
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	fwdiag "github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccWorkspace_basic(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(s, fake.Workspaces, "gfw_workspace"),
+		Steps: testAccLifecycleSteps(s, testAccLifecycle{
+			Name:       "gfw_workspace.test",
+			Collection: fake.Workspaces,
+			Create: resource.TestStep{
+				Config: testAccWorkspaceConfig("Test workspace", 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_workspace.test", "workspace_id", "test_workspace"),
+					resource.TestCheckResourceAttr("gfw_workspace.test", "viewport.0.zoom", "3"),
+					resource.TestCheckResourceAttr("gfw_workspace.test", "dataview_instances.#", "1"),
+					resource.TestCheckResourceAttrPair("gfw_workspace.test", "dataview_instances.0.dataview_id", "gfw_dataview.test", "id"),
+				),
+			},
+			Update: resource.TestStep{
+				Config: testAccWorkspaceConfig("Renamed workspace", 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_workspace.test", "workspace_id", "test_workspace"),
+					resource.TestCheckResourceAttr("gfw_workspace.test", "name", "Renamed workspace"),
+					resource.TestCheckResourceAttr("gfw_workspace.test", "viewport.0.zoom", "5"),
+				),
+			},
+		}),
+	})
+}
+
+// Attributes removed from state_config are removed from the workspace state.
+func TestAccWorkspace_stateConfig(t *testing.T) {
+	s := testAccServer(t)
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(s, fake.Workspaces, "gfw_workspace"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspaceStateConfig(`
+  state_config {
+    timebar_graph = "speed"
+    sidebar_open  = true
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gfw_workspace.test", "state_config.0.sidebar_open", "true"),
+					testAccCheckWorkspaceState(s, map[string]interface{}{"daysFromLatest": 30.0, "timebarGraph": "speed", "sidebarOpen": true}),
+				),
+			},
+			{
+				Config: testAccWorkspaceStateConfig(`
+  state_config {
+    timebar_graph = "speed"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("gfw_workspace.test", "state_config.0.sidebar_open"),
+					testAccCheckWorkspaceState(s, map[string]interface{}{"daysFromLatest": 30.0, "timebarGraph": "speed"}),
+				),
+			},
+			{
+				Config: testAccWorkspaceStateConfig(""),
+				Check:  testAccCheckWorkspaceState(s, map[string]interface{}{"daysFromLatest": 30.0}),
+			},
+		},
+	})
+}
+
+// An existing workspace is not adopted, it has to be imported.
+func TestAccWorkspace_existing(t *testing.T) {
+	s := testAccServer(t)
+	if _, err := s.Put(fake.Workspaces, map[string]interface{}{"id": "test_workspace", "name": "Declared by hand", "app": "fishing-map"}); err != nil {
+		t.Fatal(err)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccWorkspaceStateConfig(""),
+				ExpectError: regexp.MustCompile(`workspace "test_workspace" already exists, import it`),
+			},
+		},
+	})
+	if workspace, _ := s.Get(fake.Workspaces, "test_workspace"); workspace["name"] != "Declared by hand" {
+		t.Errorf("existing workspace was overwritten: %v", workspace)
+	}
+}
+
+// The write-only password is sent on create and when password_version
+// changes, it is never stored in the state.
+func TestAccWorkspace_password(t *testing.T) {
+	testAccSkipBelowTerraform(t, "1.11.0")
+	s := testAccServer(t)
+	checkPassword := func(expected string) resource.TestCheckFunc {
+		return resource.ComposeTestCheckFunc(
+			resource.TestCheckNoResourceAttr("gfw_workspace.test", "password"),
+			func(state *terraform.State) error {
+				workspace, ok := s.Get(fake.Workspaces, "test_workspace")
+				if !ok {
+					return fmt.Errorf("workspace test_workspace not found")
+				}
+				if workspace["password"] != expected {
+					return fmt.Errorf("expected password %q, got %v", expected, workspace["password"])
+				}
+				return nil
+			},
+		)
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckDestroyed(s, fake.Workspaces, "gfw_workspace"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccWorkspacePasswordConfig("secret", 1),
+				Check:  checkPassword("secret"),
+			},
+			{
+				// Without a new version the password is not sent again.
+				Config:   testAccWorkspacePasswordConfig("changed", 1),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccWorkspacePasswordConfig("changed", 2),
+				Check:  checkPassword("changed"),
+			},
+		},
+	})
+}
+
+func testAccWorkspacePasswordConfig(password string, version int) string {
+	return fmt.Sprintf(`
+resource "gfw_workspace" "test" {
+  deletion_protection = false
+  workspace_id        = "test_workspace"
+  name                = "Test workspace"
+  description         = "Workspace used by the acceptance tests"
+  app                 = "fishing-map"
+  view_access         = "password"
+  password            = %q
+  password_version    = %d
+}
+`, password, version)
+}
+
+// testAccCheckWorkspaceState compares the state JSON stored on the server.
+func testAccCheckWorkspaceState(s *fake.Server, expected map[string]interface{}) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		workspace, ok := s.Get(fake.Workspaces, "test_workspace")
+		if !ok {
+			return fmt.Errorf("workspace test_workspace not found")
+		}
+		if !reflect.DeepEqual(workspace["state"], expected) {
+			return fmt.Errorf("expected workspace state %v, got %v", expected, workspace["state"])
+		}
+		return nil
+	}
+}
+
+func testAccWorkspaceStateConfig(stateConfig string) string {
+	return fmt.Sprintf(`
+resource "gfw_workspace" "test" {
+  deletion_protection = false
+  workspace_id        = "test_workspace"
+  name                = "Test workspace"
+  description         = "Workspace used by the acceptance tests"
+  app                 = "fishing-map"
+  state               = jsonencode({ daysFromLatest = 30 })
+%s}
+`, stateConfig)
+}
+
+func testAccWorkspaceConfig(name string, zoom int) string {
+	return fmt.Sprintf(`
+resource "gfw_dataset" "test" {
+  deletion_protection = false
+  dataset_id          = "test-mpa:v1"
+  name                = "Protected areas"
+  type                = "context-layer:v1"
+  description         = "Marine protected areas"
+  category            = "context-layer"
+  subcategory         = "user"
+}
+
+resource "gfw_dataview" "test" {
+  slug        = "test-mpa"
+  name        = "Protected areas"
+  description = "Marine protected areas"
+  category    = "context"
+  app         = "fishing-map"
+
+  config {
+    type     = "CONTEXT"
+    datasets = [gfw_dataset.test.id]
+  }
+}
+
+resource "gfw_workspace" "test" {
+  workspace_id = "test_workspace"
+  name         = %q
+  description  = "Workspace used by the acceptance tests"
+  category     = "marine-manager"
+  app          = "fishing-map"
+  start_at     = "2023-01-01T00:00:00.000Z"
+  end_at       = "2024-01-01T00:00:00.000Z"
+  state        = jsonencode({ daysFromLatest = 30 })
+
+  viewport {
+    zoom      = %d
+    latitude  = 10
+    longitude = -20
+  }
+
+  dataview_instances {
+    id          = "context-mpa"
+    dataview_id = gfw_dataview.test.id
+    config      = jsonencode({ visible = true })
+  }
+}
+`, name, zoom)
+}
+
+func TestValidateWorkspaceTimeRange(t *testing.T) {
+	for _, tc := range []struct {
+		startAt, endAt string
+		expected       []string
+	}{
+		{"2023-01-01T00:00:00.000Z", "2024-01-01T00:00:00.000Z", nil},
+		{"", "2024-01-01T00:00:00.000Z", nil},
+		{"2024-01-01T00:00:00.000Z", "2023-01-01T00:00:00.000Z", []string{"end_at"}},
+		{"2023-01-01", "2024-01-01T00:00:00.000Z", []string{"start_at"}},
+		{"2023-01-01T00:00:00.000Z", "tomorrow", []string{"end_at"}},
+		{"yesterday", "tomorrow", []string{"start_at", "end_at"}},
+	} {
+		var attributes []string
+		for _, d := range validateWorkspaceTimeRange(tc.startAt, tc.endAt) {
+			attributes = append(attributes, d.(fwdiag.DiagnosticWithPath).Path().String())
+		}
+		if !reflect.DeepEqual(attributes, tc.expected) {
+			t.Errorf("start_at %q, end_at %q: got errors on %v, expected %v", tc.startAt, tc.endAt, attributes, tc.expected)
+		}
+	}
+}
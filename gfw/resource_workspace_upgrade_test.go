@@ -0,0 +1,141 @@
+package gfw
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// testAccSDKv2Release is the last release serving gfw_workspace from the SDKv2
+// provider. Without access to the registry, TF_CLI_CONFIG_FILE can point to a
+// filesystem mirror holding it.
+const testAccSDKv2Release = "0.2.0"
+
+// Workspaces created by the SDKv2 release must plan no changes once their
+// state is upgraded, the attributes added since taking their defaults.
+func TestAccWorkspace_upgradeFromSDKv2(t *testing.T) {
+	for name, tc := range map[string]struct {
+		address string
+		config  func(dataviewID string) string
+		public  string
+	}{
+		"minimal": {
+			address: "gfw_workspace.minimal",
+			config:  func(string) string { return testAccWorkspaceMinimalConfig },
+			public:  "false",
+		},
+		"full": {
+			address: "gfw_workspace.full",
+			config:  func(dataviewID string) string { return fmt.Sprintf(testAccWorkspaceFullConfig, 160, dataviewID) },
+			public:  "true",
+		},
+		"longitude": {
+			address: "gfw_workspace.full",
+			config:  func(dataviewID string) string { return fmt.Sprintf(testAccWorkspaceFullConfig, 200, dataviewID) },
+			public:  "true",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := testAccServer(t)
+			if _, err := s.Put(fake.Datasets, map[string]interface{}{"id": "public-mpa:v1"}); err != nil {
+				t.Fatal(err)
+			}
+			dataviewID, err := s.Put(fake.Dataviews, map[string]interface{}{"name": "Context MPA"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The provider under test uses the source address of the release.
+			t.Setenv("TF_ACC_PROVIDER_NAMESPACE", "globalfishingwatch")
+			released := testAccWorkspaceUpgradeConfig(tc.config(dataviewID), testAccSDKv2Release)
+			current := testAccWorkspaceUpgradeConfig(tc.config(dataviewID), "")
+			resource.Test(t, resource.TestCase{
+				CheckDestroy: testAccCheckDestroyed(s, fake.Workspaces, "gfw_workspace"),
+				Steps: []resource.TestStep{
+					{
+						ExternalProviders: map[string]resource.ExternalProvider{
+							"gfw": {Source: "GlobalFishingWatch/gfw", VersionConstraint: testAccSDKv2Release},
+						},
+						Config: released,
+					},
+					{
+						ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+						Config:                   current,
+						PlanOnly:                 true,
+					},
+					{
+						ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+						Config:                   current,
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestCheckResourceAttr(tc.address, "deletion_protection", "false"),
+							resource.TestCheckResourceAttr(tc.address, "public", tc.public),
+							resource.TestCheckResourceAttr(tc.address, "ignore_external_dataview_instances", "false"),
+							resource.TestCheckResourceAttr(tc.address, "state_config.#", "0"),
+						),
+					},
+				},
+			})
+		})
+	}
+}
+
+// testAccWorkspaceUpgradeConfig declares the source of the provider, so the
+// release and the provider under test manage the same resources.
+func testAccWorkspaceUpgradeConfig(config string, version string) string {
+	var constraint strings.Builder
+	if version != "" {
+		constraint.WriteString(fmt.Sprintf("\n      version = %q", version))
+	}
+	return fmt.Sprintf(`
+terraform {
+  required_providers {
+    gfw = {
+      source = "GlobalFishingWatch/gfw"%s
+    }
+  }
+}
+`, constraint.String()) + config
+}
+
+// The configurations below only use the attributes of the SDKv2 release.
+
+const testAccWorkspaceMinimalConfig = `
+resource "gfw_workspace" "minimal" {
+  name        = "Minimal workspace"
+  description = "Workspace without optional attributes"
+  app         = "fishing-map"
+}
+`
+
+const testAccWorkspaceFullConfig = `
+resource "gfw_workspace" "full" {
+  workspace_id = "full_workspace-public"
+  name         = "Full workspace"
+  description  = "Workspace with every attribute"
+  category     = "marine-manager"
+  app          = "fishing-map"
+  public       = true
+  start_at     = "2023-01-01T00:00:00.000Z"
+  end_at       = "2024-01-01T00:00:00.000Z"
+  state        = jsonencode({ daysFromLatest = 30 })
+  dataviews    = [1, 2]
+
+  viewport {
+    zoom      = 4
+    latitude  = -10.5
+    longitude = %d
+  }
+
+  dataview_instances {
+    id          = "context-mpa"
+    category    = "context"
+    dataview_id = "%s"
+    config      = jsonencode({ visible = true })
+    datasets_config = [
+      jsonencode({ datasetId = "public-mpa:v1", endpoint = "context-tiles", params = [] }),
+    ]
+  }
+}
+`
@@ -29,9 +29,22 @@ func Exists(i int, array []int) bool {
 	return false
 }
 
+func ContainsString(array []string, s string) bool {
+	for _, v := range array {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
 func ConvertArrayInterfaceToArrayString(arrayInt []interface{}) []string {
 	arrayStr := make([]string, len(arrayInt))
 	for i, v := range arrayInt {
+		// Empty strings in lists are read back from the schema as nil.
+		if v == nil {
+			continue
+		}
 		arrayStr[i] = fmt.Sprint(v)
 	}
 	return arrayStr
@@ -2,34 +2,151 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"strings"
 
 	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 )
 
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := export(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		ok, err := validate(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
+
 	var debugMode bool
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return gfw.Provider()
-		},
+	ctx := context.Background()
+	sdkProvider := gfw.Provider()
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(gfw.NewFrameworkProvider(sdkProvider)),
+	)
+	if err != nil {
+		log.Fatal(err.Error())
 	}
 
+	var serveOpts []tf5server.ServeOpt
 	if debugMode {
-		err := plugin.Debug(context.Background(), "GlobalFishingWatch/gfw", opts)
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+
+	err = tf5server.Serve("registry.terraform.io/GlobalFishingWatch/gfw", muxServer.ProviderServer, serveOpts...)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+// export writes the Terraform configuration and import blocks of the objects
+// of a GFW API, so they can be managed by the provider.
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	url := flags.String("url", os.Getenv("GFW_URL"), "URL of the GFW API, defaults to GFW_URL")
+	token := flags.String("token", os.Getenv("GFW_TOKEN"), "token of the GFW API, defaults to GFW_TOKEN")
+	resources := flags.String("resources", "", "comma separated resource types to export, such as gfw_dataset")
+	types := flags.String("types", "", "comma separated dataset types or dataview config types to export")
+	categories := flags.String("categories", "", "comma separated categories to export")
+	prefix := flags.String("prefix", "", "prefix of the ID or the name of the objects to export")
+	output := flags.String("output", "", "file written, defaults to the standard output")
+	flags.Parse(args)
+	if *url == "" || *token == "" {
+		return fmt.Errorf("the url and token of the GFW API are required")
+	}
+
+	c, err := api.NewClient(*url, *token)
+	if err != nil {
+		return err
+	}
+	w := os.Stdout
+	if *output != "" {
+		w, err = os.Create(*output)
 		if err != nil {
-			log.Fatal(err.Error())
+			return err
 		}
-		return
+		defer w.Close()
 	}
+	return gfw.Export(context.Background(), c, w, gfw.ExportOptions{
+		Resources:  splitList(*resources),
+		Types:      splitList(*types),
+		Categories: splitList(*categories),
+		Prefix:     *prefix,
+	})
+}
 
-	plugin.Serve(opts)
+// validate checks dataset and dataview definitions without calling the API
+// and prints the problems found. It returns false when there are errors.
+func validate(args []string) (bool, error) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	format := flags.String("format", "text", "output format, text or json")
+	enumsFile := flags.String("enums-file", "", "JSON file overriding the enums, as the provider enums_file setting")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return false, fmt.Errorf("usage: terraform-provider-gfw validate [-format text|json] [-enums-file file] file...")
+	}
 
+	problems, err := gfw.ValidateDefinitions(flags.Args(), *enumsFile)
+	if err != nil {
+		return false, err
+	}
+	ok := true
+	for _, problem := range problems {
+		if problem.Severity == "error" {
+			ok = false
+		}
+	}
+
+	switch *format {
+	case "json":
+		if problems == nil {
+			problems = []gfw.DefinitionProblem{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return ok, encoder.Encode(problems)
+	case "text":
+		for _, problem := range problems {
+			location := problem.File
+			if problem.ID != "" {
+				location = fmt.Sprintf("%s:%s", location, problem.ID)
+			}
+			if problem.Path != "" {
+				location = fmt.Sprintf("%s:%s", location, problem.Path)
+			}
+			fmt.Printf("%s: %s: %s\n", location, problem.Severity, problem.Message)
+		}
+		return ok, nil
+	}
+	return false, fmt.Errorf("unknown format %s", *format)
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
 }
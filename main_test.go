@@ -0,0 +1,63 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/globalfishingwatch.org/terraform-provider-gfw/gfw/api/fake"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// The export command writes only the configuration to the standard output, so
+// it can be redirected to a .tf file, and reads every page of the lists.
+func TestExportCommand(t *testing.T) {
+	s := fake.NewServer()
+	s.PageSize = 1
+	t.Cleanup(s.Close)
+	objects := []struct {
+		collection string
+		object     map[string]interface{}
+	}{
+		{fake.Actions, map[string]interface{}{"name": "read", "description": "Read access"}},
+		{fake.Datasets, map[string]interface{}{"id": "public-mpa:v1", "name": "Protected areas", "type": "context-layer:v1", "category": "context-layer"}},
+		{fake.Datasets, map[string]interface{}{"id": "public-eez:v1", "name": "Exclusive economic zones", "type": "context-layer:v1", "category": "context-layer"}},
+	}
+	for _, o := range objects {
+		if _, err := s.Put(o.collection, o.object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "gfw.tf")
+	stdout, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := os.Stdout
+	os.Stdout = stdout
+	err = export([]string{"-url", s.URL, "-token", fake.Token, "-resources", "gfw_action,gfw_dataset"})
+	os.Stdout = previous
+	stdout.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, diags := hclparse.NewParser().ParseHCL(content, "gfw.tf"); diags.HasErrors() {
+		t.Fatalf("invalid configuration written: %v\n%s", diags, content)
+	}
+	for _, expected := range []string{
+		`resource "gfw_action"`,
+		`resource "gfw_dataset" "public_mpa_v1"`,
+		`resource "gfw_dataset" "public_eez_v1"`,
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %s in the output:\n%s", expected, content)
+		}
+	}
+}